@@ -27,7 +27,8 @@ func ConnectDatabase() {
         getEnvOrDefault("DB_SSLMODE", "disable"),
     )
 
-    database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+    // TranslateError: unique violation dari Postgres menjadi gorm.ErrDuplicatedKey
+    database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
     if err != nil {
         log.Fatal("Failed to connect to database:", err)
     }
@@ -6,12 +6,14 @@ import (
     "os"
     
     firebase "firebase.google.com/go/v4"
+    "firebase.google.com/go/v4/auth"
     "firebase.google.com/go/v4/messaging"
     "google.golang.org/api/option"
 )
 
 var FirebaseApp *firebase.App
 var FirebaseMessaging *messaging.Client
+var FirebaseAuth *auth.Client
 
 func InitFirebase() {
     credentialsPath := getEnvOrDefault("FIREBASE_CREDENTIALS_PATH", "./firebase-credentials.json")
@@ -33,6 +35,13 @@ func InitFirebase() {
 
     FirebaseApp = app
 
+    authClient, err := app.Auth(context.Background())
+    if err != nil {
+        log.Printf("⚠️  Error initializing Firebase auth: %v", err)
+    } else {
+        FirebaseAuth = authClient
+    }
+
     messagingClient, err := app.Messaging(context.Background())
     if err != nil {
         log.Printf("⚠️  Error initializing Firebase messaging: %v", err)
@@ -41,4 +50,4 @@ func InitFirebase() {
 
     FirebaseMessaging = messagingClient
     log.Println("✅ Firebase initialized successfully")
-}
\ No newline at end of file
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+    "log"
+    "os"
+)
+
+type SMTPConfig struct {
+    Host     string
+    Port     string
+    Username string
+    Password string
+    From     string
+}
+
+// SMTP bernilai nil jika SMTP_HOST tidak diisi; channel email dinonaktifkan
+var SMTP *SMTPConfig
+
+func InitSMTP() {
+    host := os.Getenv("SMTP_HOST")
+    if host == "" {
+        log.Println("⚠️  SMTP_HOST not set, email notifications will be disabled")
+        return
+    }
+
+    SMTP = &SMTPConfig{
+        Host:     host,
+        Port:     getEnvOrDefault("SMTP_PORT", "587"),
+        Username: os.Getenv("SMTP_USERNAME"),
+        Password: os.Getenv("SMTP_PASSWORD"),
+        From:     getEnvOrDefault("SMTP_FROM", "TaskFlow <no-reply@taskflow.local>"),
+    }
+    log.Printf("✅ SMTP configured: %s:%s", SMTP.Host, SMTP.Port)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+    "net/http"
+    "strconv"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+
+    "github.com/gin-gonic/gin"
+)
+
+// authorizeUserParam memastikan user yang login adalah pemilik resource dengan ID dari URL,
+// atau memiliki permission untuk mengakses data user lain (admin / auditor)
+func authorizeUserParam(c *gin.Context, param string, permission string) (uint, bool) {
+    userID, err := strconv.ParseUint(param, 10, 64)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid user ID",
+        })
+        return 0, false
+    }
+    return uint(userID), authorizeUserID(c, uint(userID), permission)
+}
+
+// parseIDParam - parse ID numerik dari URL. Jangan teruskan c.Param langsung ke First:
+// GORM memperlakukan string non-numerik sebagai potongan SQL mentah
+func parseIDParam(c *gin.Context, name string, label string) (uint, bool) {
+    id, err := strconv.ParseUint(c.Param(name), 10, 64)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid " + label + " ID",
+        })
+        return 0, false
+    }
+    return uint(id), true
+}
+
+func authorizeUserID(c *gin.Context, userID uint, permission string) bool {
+    user, ok := middleware.CurrentUser(c)
+    if !ok || (user.ID != userID && !user.Can(permission)) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error": "You do not have access to this resource",
+        })
+        return false
+    }
+    return true
+}
+
+func authorizeTask(c *gin.Context, task models.Task, permission string) bool {
+    user, ok := middleware.CurrentUser(c)
+    if !ok || (user.ID != task.UserID && !user.Can(permission)) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error": "You do not have access to this task",
+        })
+        return false
+    }
+    return true
+}
+
+// actorID - ID user yang melakukan request, dicatat di outbox event (0 jika belum ada user, mis. registrasi)
+func actorID(c *gin.Context) uint {
+    user, _ := middleware.CurrentUser(c)
+    return user.ID
+}
+
+// auditContext - actor, request ID, IP dan alasan opsional (header X-Audit-Reason) untuk audit log
+func auditContext(c *gin.Context) services.AuditContext {
+    user, _ := middleware.CurrentUser(c)
+    return services.AuditContext{
+        ActorID:    user.ID,
+        ActorEmail: user.Email,
+        RequestID:  middleware.GetRequestID(c),
+        IPAddress:  c.ClientIP(),
+        UserAgent:  c.Request.UserAgent(),
+        Reason:     c.GetHeader("X-Audit-Reason"),
+    }
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+    "net/http"
+    "strconv"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+func GetMyAPIKeys(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    keys, err := services.NewAPIKeyService().ListKeys(user.ID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch API keys",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": keys,
+        "count": len(keys),
+    })
+}
+
+func CreateMyAPIKey(c *gin.Context) {
+    var req models.CreateAPIKeyRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid expiration",
+            "details": "expires_at must be in the future",
+        })
+        return
+    }
+
+    user, _ := middleware.CurrentUser(c)
+    for _, scope := range req.Scopes {
+        if scope == models.ScopeAdmin && !user.Can(models.PermissionManageUsers) {
+            c.JSON(http.StatusForbidden, gin.H{
+                "error": "Only admins can create keys with the admin scope",
+            })
+            return
+        }
+    }
+
+    key, apiKey, err := services.NewAPIKeyService().CreateKey(user, req)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to create API key",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "message": "API key created successfully, store it now as it will not be shown again",
+        "data": gin.H{
+            "api_key": apiKey,
+            "key":     key,
+        },
+    })
+}
+
+func RevokeMyAPIKey(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    keyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "API key not found",
+        })
+        return
+    }
+
+    revoked, err := services.NewAPIKeyService().RevokeKey(user.ID, uint(keyID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to revoke API key",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if !revoked {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "API key not found",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "API key revoked successfully",
+    })
+}
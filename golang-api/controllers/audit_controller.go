@@ -0,0 +1,139 @@
+package controllers
+
+import (
+    "net/http"
+    "strconv"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+const (
+    defaultAuditLogLimit = 50
+    maxAuditLogLimit     = 200
+)
+
+// GetAuditLogs - audit log terbaru dulu, untuk admin dan auditor. Filter: entity_type, entity_id, actor_id,
+// action, request_id, from / to (RFC3339). Pagination pakai ?limit= dan ?cursor= seperti inbox notifikasi.
+func GetAuditLogs(c *gin.Context) {
+    limit := defaultAuditLogLimit
+    if value := c.Query("limit"); value != "" {
+        n, err := strconv.Atoi(value)
+        if err != nil || n < 1 || n > maxAuditLogLimit {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "limit must be between 1 and 200",
+            })
+            return
+        }
+        limit = n
+    }
+
+    query := config.DB.Model(&models.AuditLog{})
+    if entityType := c.Query("entity_type"); entityType != "" {
+        if entityType != models.AggregateTask && entityType != models.AggregateUser && entityType != models.AggregateCategory {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "entity_type must be one of task, user, category",
+            })
+            return
+        }
+        query = query.Where("entity_type = ?", entityType)
+    }
+    for _, param := range []string{"entity_id", "actor_id", "cursor"} {
+        value := c.Query(param)
+        if value == "" {
+            continue
+        }
+        id, err := strconv.ParseUint(value, 10, 64)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "invalid " + param,
+            })
+            return
+        }
+        if param == "cursor" {
+            query = query.Where("id < ?", id)
+        } else {
+            query = query.Where(param+" = ?", id)
+        }
+    }
+    if action := c.Query("action"); action != "" {
+        query = query.Where("action = ?", action)
+    }
+    if requestID := c.Query("request_id"); requestID != "" {
+        query = query.Where("request_id = ?", requestID)
+    }
+    for _, param := range []string{"from", "to"} {
+        value := c.Query(param)
+        if value == "" {
+            continue
+        }
+        t, err := time.Parse(time.RFC3339, value)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": param + " must be an RFC3339 timestamp",
+            })
+            return
+        }
+        if param == "from" {
+            query = query.Where("created_at >= ?", t)
+        } else {
+            query = query.Where("created_at < ?", t)
+        }
+    }
+
+    var logs []models.AuditLog
+    if err := query.Order("id DESC").Limit(limit + 1).Find(&logs).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch audit logs",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    hasMore := len(logs) > limit
+    if hasMore {
+        logs = logs[:limit]
+    }
+
+    pagination := gin.H{
+        "limit":       limit,
+        "has_more":    hasMore,
+        "next_cursor": nil,
+    }
+    if hasMore {
+        pagination["next_cursor"] = strconv.FormatUint(uint64(logs[len(logs)-1].ID), 10)
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": logs,
+        "count": len(logs),
+        "pagination": pagination,
+    })
+}
+
+func GetAuditLog(c *gin.Context) {
+    entryID, ok := parseIDParam(c, "id", "audit log")
+    if !ok {
+        return
+    }
+
+    var entry models.AuditLog
+    if err := config.DB.First(&entry, entryID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Audit log not found",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": entry,
+    })
+}
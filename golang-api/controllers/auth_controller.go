@@ -0,0 +1,269 @@
+package controllers
+
+import (
+    "errors"
+    "net/http"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
+)
+
+// recentLoginWindow - user tanpa password harus login dalam jeda ini sebelum boleh set password atau ganti email
+const recentLoginWindow = 5 * time.Minute
+
+func Register(c *gin.Context) {
+    var req models.RegisterRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    email := strings.ToLower(strings.TrimSpace(req.Email))
+
+    var count int64
+    config.DB.Model(&models.User{}).Where("LOWER(email) = ?", email).Count(&count)
+    if count > 0 {
+        c.JSON(http.StatusConflict, gin.H{
+            "error": "Email is already registered",
+        })
+        return
+    }
+
+    authService := services.NewAuthService()
+    hash, err := authService.HashPassword(req.Password)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to hash password",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    user := models.User{
+        Name:     req.Name,
+        Email:    email,
+        Password: hash,
+    }
+    err = config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&user).Error; err != nil {
+            return err
+        }
+        if err := recordUserCreatedAudit(tx, c, user); err != nil {
+            return err
+        }
+        return recordUserEvent(tx, c, models.EventUserCreated, user, nil)
+    })
+    if errors.Is(err, gorm.ErrDuplicatedKey) {
+        // Register bersamaan dengan email yang sama; unique index LOWER(email) yang menolak
+        c.JSON(http.StatusConflict, gin.H{
+            "error": "Email is already registered",
+        })
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to create user",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    token, session, err := authService.CreateSession(user, c.Request.UserAgent(), c.ClientIP())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to create session",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "message": "User registered successfully",
+        "data": gin.H{
+            "user":       user,
+            "token":      token,
+            "expires_at": session.ExpiresAt,
+        },
+    })
+}
+
+func Login(c *gin.Context) {
+    var req models.LoginRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    authService := services.NewAuthService()
+    user, err := authService.Authenticate(strings.TrimSpace(req.Email), req.Password)
+    if err != nil {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error": "Invalid email or password",
+        })
+        return
+    }
+
+    token, session, err := authService.CreateSession(user, c.Request.UserAgent(), c.ClientIP())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to create session",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Logged in successfully",
+        "data": gin.H{
+            "user":       user,
+            "token":      token,
+            "expires_at": session.ExpiresAt,
+        },
+    })
+}
+
+func Logout(c *gin.Context) {
+    session, ok := middleware.CurrentSession(c)
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Logout is only available for email/password sessions",
+        })
+        return
+    }
+
+    if err := services.NewAuthService().RevokeSession(session.ID); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to revoke session",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Logged out successfully",
+    })
+}
+
+func LogoutAll(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    if err := services.NewAuthService().RevokeUserSessions(config.DB, user.ID, 0); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to revoke sessions",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "All sessions revoked successfully",
+    })
+}
+
+func ChangePassword(c *gin.Context) {
+    var req models.ChangePasswordRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    current, _ := middleware.CurrentUser(c)
+
+    var user models.User
+    if err := config.DB.First(&user, current.ID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "User not found",
+        })
+        return
+    }
+
+    if !confirmCredentials(c, user, req.CurrentPassword) {
+        return
+    }
+
+    authService := services.NewAuthService()
+    hash, err := authService.HashPassword(req.NewPassword)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to hash password",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    // Hash password tidak pernah masuk audit log, hanya fakta bahwa password diganti
+    change := models.AuditChange{To: "[redacted]"}
+    if user.Password != "" {
+        change.From = "[redacted]"
+    }
+    user.Password = hash
+
+    // Semua session lain dicabut di transaksi yang sama; session yang sedang dipakai tetap aktif
+    session, _ := middleware.CurrentSession(c)
+    err = config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&user).Update("password", user.Password).Error; err != nil {
+            return err
+        }
+        if err := authService.RevokeUserSessions(tx, user.ID, session.ID); err != nil {
+            return err
+        }
+        return services.RecordAuditChanges(tx, auditContext(c), models.AuditUserPasswordChange, models.AggregateUser, user.ID,
+            map[string]models.AuditChange{"password": change})
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to change password",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Password changed successfully",
+    })
+}
+
+// confirmCredentials - perubahan sensitif (password, email) butuh password saat ini. User Firebase yang belum
+// punya password tidak bisa membuktikannya, jadi wajib login ulang (auth_time baru); token lama yang bocor
+// tidak cukup untuk mengambil alih akun
+func confirmCredentials(c *gin.Context, user models.User, currentPassword string) bool {
+    if user.Password == "" {
+        authTime, ok := middleware.CurrentAuthTime(c)
+        if !ok || time.Since(authTime) > recentLoginWindow {
+            c.JSON(http.StatusUnauthorized, gin.H{
+                "error": "Recent login required",
+                "details": "sign in again before making this change",
+            })
+            return false
+        }
+        return true
+    }
+
+    if !services.NewAuthService().CheckPassword(user.Password, currentPassword) {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error": "Current password is incorrect",
+        })
+        return false
+    }
+    return true
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+    "encoding/json"
+    "errors"
+    "net/http"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "golang.org/x/net/websocket"
+)
+
+const (
+    boardMaxMessageBytes = 4 << 10
+    boardWriteTimeout    = 10 * time.Second
+    // boardIdleTimeout - client wajib mengirim pesan (mis. {"type":"ping"}) dalam jeda ini
+    boardIdleTimeout = 90 * time.Second
+)
+
+// BoardSocket - WebSocket untuk board kolaboratif. Client mengirim {"type":"subscribe","topic":"category:3"},
+// lalu menerima perubahan task dan presence dari user lain yang membuka board yang sama.
+func BoardSocket(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    server := websocket.Server{
+        // Origin apa pun diterima karena token hanya dibaca dari header Authorization/X-API-Key atau
+        // ?access_token=, tidak pernah dari cookie. Halaman dari origin lain tidak bisa membuka socket
+        // atas nama user tanpa memegang token-nya (cross-site WebSocket hijacking). Jika suatu saat auth
+        // memakai cookie, Origin wajib dicek di sini.
+        Handshake: func(*websocket.Config, *http.Request) error { return nil },
+        Handler: func(conn *websocket.Conn) {
+            serveBoardConnection(conn, user)
+        },
+    }
+    server.ServeHTTP(c.Writer, c.Request)
+}
+
+func serveBoardConnection(conn *websocket.Conn, user models.User) {
+    defer conn.Close()
+    conn.MaxPayloadBytes = boardMaxMessageBytes
+
+    hub := services.Boards()
+    client, ok := hub.Connect(user)
+    if !ok {
+        websocket.JSON.Send(conn, services.BoardMessage{Type: services.BoardMessageError, Error: "server is shutting down"})
+        return
+    }
+    defer hub.Disconnect(client)
+
+    // Writer: berhenti saat antrian ditutup (client lambat, disconnect atau shutdown), lalu menutup koneksi
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        defer conn.Close()
+        for message := range client.Outgoing() {
+            conn.SetWriteDeadline(time.Now().Add(boardWriteTimeout))
+            if _, err := conn.Write(message); err != nil {
+                hub.Disconnect(client)
+                return
+            }
+        }
+    }()
+
+    for {
+        conn.SetReadDeadline(time.Now().Add(boardIdleTimeout))
+
+        var command services.BoardCommand
+        if err := websocket.JSON.Receive(conn, &command); err != nil {
+            if errors.Is(err, websocket.ErrFrameTooLarge) {
+                hub.Send(client, services.BoardMessage{Type: services.BoardMessageError, Error: "message too large"})
+                continue
+            }
+            var syntaxErr *json.SyntaxError
+            var typeErr *json.UnmarshalTypeError
+            if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+                hub.Send(client, services.BoardMessage{Type: services.BoardMessageError, Error: "invalid message"})
+                continue
+            }
+            break
+        }
+        handleBoardCommand(hub, client, user, command)
+    }
+
+    hub.Disconnect(client)
+    <-done
+}
+
+func handleBoardCommand(hub *services.BoardHub, client *services.BoardClient, user models.User, command services.BoardCommand) {
+    switch command.Type {
+    case services.BoardCommandSubscribe:
+        shared, err := authorizeBoardTopic(user, command.Topic)
+        if err == nil {
+            err = hub.Subscribe(client, command.Topic, shared)
+        }
+        if err != nil {
+            hub.Send(client, services.BoardMessage{Type: services.BoardMessageError, Topic: command.Topic, Error: err.Error()})
+        }
+    case services.BoardCommandUnsubscribe:
+        hub.Unsubscribe(client, command.Topic)
+    case services.BoardCommandPing:
+        hub.Send(client, services.BoardMessage{Type: services.BoardMessagePong})
+    default:
+        hub.Send(client, services.BoardMessage{Type: services.BoardMessageError, Error: "unknown message type"})
+    }
+}
+
+// authorizeBoardTopic mengecek akses ke topic. Board kategori private dibagi ke semua anggotanya (shared);
+// di kategori global setiap user hanya melihat task-nya sendiri.
+func authorizeBoardTopic(user models.User, topic string) (bool, error) {
+    kind, id, ok := services.ParseBoardTopic(topic)
+    if !ok {
+        return false, errors.New("invalid topic, expected category:<id> or user:<id>")
+    }
+
+    if kind == services.BoardTopicUser {
+        if id != user.ID && !user.Can(models.PermissionReadAllData) {
+            return false, errors.New("you do not have access to this board")
+        }
+        return true, nil
+    }
+
+    var category models.Category
+    if err := accessibleCategories(user.ID).First(&category, id).Error; err != nil {
+        return false, errors.New("category not found")
+    }
+    return category.IsPrivate, nil
+}
@@ -1,17 +1,33 @@
 package controllers
 
 import (
+    "errors"
+    "fmt"
     "net/http"
+    "regexp"
+    "strconv"
+    "strings"
     "taskflow-api/config"
+    "taskflow-api/middleware"
     "taskflow-api/models"
-    
+    "taskflow-api/services"
+
     "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
 )
 
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// maxSlugAttempts - batas percobaan ulang saat slug keburu dipakai request lain
+const maxSlugAttempts = 5
+
 func GetCategories(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
     var categories []models.Category
-    result := config.DB.Order("name ASC").Find(&categories)
-    
+    result := accessibleCategories(user.ID).Order("name ASC").Find(&categories)
+
     if result.Error != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to fetch categories",
@@ -19,7 +35,7 @@ func GetCategories(c *gin.Context) {
         })
         return
     }
-    
+
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data": categories,
@@ -28,20 +44,411 @@ func GetCategories(c *gin.Context) {
 }
 
 func GetCategoryById(c *gin.Context) {
-    id := c.Param("id")
-    
+    id, ok := parseIDParam(c, "id", "category")
+    if !ok {
+        return
+    }
+    user, _ := middleware.CurrentUser(c)
+
+    // Task di kategori global bisa milik siapa saja, jadi hanya tampilkan task milik user
+    // kecuali user boleh membaca seluruh data
+    tasksScope := func(db *gorm.DB) *gorm.DB {
+        if user.Can(models.PermissionReadAllData) {
+            return db
+        }
+        return db.Where("user_id = ?", user.ID)
+    }
+
     var category models.Category
-    result := config.DB.Preload("Tasks").First(&category, id)
-    
+    result := accessibleCategories(user.ID).Preload("Tasks", tasksScope).First(&category, id)
+
     if result.Error != nil {
         c.JSON(http.StatusNotFound, gin.H{
             "error": "Category not found",
         })
         return
     }
-    
+
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data": category,
     })
-}
\ No newline at end of file
+}
+
+func CreateCategory(c *gin.Context) {
+    var req models.CreateCategoryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if req.Color != "" && !colorPattern.MatchString(req.Color) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid color",
+            "details": "color must be a hex value like #3B82F6",
+        })
+        return
+    }
+
+    // Member selalu membuat kategori private, admin default membuat kategori global
+    user, _ := middleware.CurrentUser(c)
+    isPrivate := true
+    if user.Can(models.PermissionManageCategories) {
+        isPrivate = req.IsPrivate != nil && *req.IsPrivate
+    }
+
+    base, err := slugBase(req.Name)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid category name",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    var category models.Category
+    err = withUniqueSlug(base, 0, func(slug string) error {
+        category = models.Category{
+            Name:        strings.TrimSpace(req.Name),
+            Slug:        slug,
+            Color:       getOrDefault(req.Color, "#3B82F6"),
+            Description: req.Description,
+            IsPrivate:   isPrivate,
+        }
+
+        return config.DB.Transaction(func(tx *gorm.DB) error {
+            if err := tx.Create(&category).Error; err != nil {
+                return err
+            }
+            if isPrivate {
+                if err := tx.Model(&category).Association("Users").Append(&user); err != nil {
+                    return err
+                }
+            }
+            if err := services.RecordAudit(tx, auditContext(c), models.AuditCategoryCreate, models.AggregateCategory, category.ID, nil, category); err != nil {
+                return err
+            }
+            return recordCategoryEvent(tx, c, models.EventCategoryCreated, category, nil)
+        })
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to create category",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "message": "Category created successfully",
+        "data": category,
+    })
+}
+
+func UpdateCategory(c *gin.Context) {
+    category, ok := findManageableCategory(c)
+    if !ok {
+        return
+    }
+
+    var req models.UpdateCategoryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if req.Color != "" && !colorPattern.MatchString(req.Color) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid color",
+            "details": "color must be a hex value like #3B82F6",
+        })
+        return
+    }
+
+    before := category
+    base := ""
+    if req.Name != "" && strings.TrimSpace(req.Name) != category.Name {
+        var err error
+        base, err = slugBase(req.Name)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid category name",
+                "details": err.Error(),
+            })
+            return
+        }
+        category.Name = strings.TrimSpace(req.Name)
+    }
+    if req.Color != "" {
+        category.Color = req.Color
+    }
+    if req.Description != "" {
+        category.Description = req.Description
+    }
+
+    save := func(slug string) error {
+        if slug != "" {
+            category.Slug = slug
+        }
+        return config.DB.Transaction(func(tx *gorm.DB) error {
+            if err := tx.Save(&category).Error; err != nil {
+                return err
+            }
+            if err := services.RecordAudit(tx, auditContext(c), models.AuditCategoryUpdate, models.AggregateCategory, category.ID, before, category); err != nil {
+                return err
+            }
+            return recordCategoryEvent(tx, c, models.EventCategoryUpdated, category, nil)
+        })
+    }
+
+    var err error
+    if base != "" {
+        err = withUniqueSlug(base, category.ID, save)
+    } else {
+        err = save("")
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update category",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Category updated successfully",
+        "data": category,
+    })
+}
+
+// DeleteCategory menghapus kategori; jika masih dipakai task, wajib ada ?reassign_to=<category_id>
+func DeleteCategory(c *gin.Context) {
+    category, ok := findManageableCategory(c)
+    if !ok {
+        return
+    }
+
+    // Unscoped: task yang di-soft-delete tetap mereferensikan kategori (foreign key),
+    // jadi ikut dihitung dan ikut dipindah
+    var taskCount int64
+    if err := config.DB.Unscoped().Model(&models.Task{}).Where("category_id = ?", category.ID).Count(&taskCount).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to delete category",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    var target models.Category
+    reassignTo := c.Query("reassign_to")
+    if taskCount > 0 {
+        if reassignTo == "" {
+            c.JSON(http.StatusConflict, gin.H{
+                "error": "Category is still used by tasks",
+                "details": fmt.Sprintf("%d tasks use this category, pass reassign_to with a target category ID", taskCount),
+            })
+            return
+        }
+
+        user, _ := middleware.CurrentUser(c)
+        targetID, err := strconv.ParseUint(reassignTo, 10, 64)
+        if err == nil {
+            err = accessibleCategories(user.ID).First(&target, uint(targetID)).Error
+        }
+        if err != nil || target.ID == category.ID {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid reassignment target",
+            })
+            return
+        }
+
+        // Task di kategori global milik banyak user, jadi target harus kategori global juga
+        if !category.IsPrivate && target.IsPrivate {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid reassignment target",
+                "details": "tasks from a global category can only be moved to another global category",
+            })
+            return
+        }
+    }
+
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if target.ID != 0 {
+            // Task aktif dimuat dulu supaya tiap task mendapat event sendiri (SSE, board, webhook)
+            var moved []models.Task
+            if err := tx.Where("category_id = ?", category.ID).Find(&moved).Error; err != nil {
+                return err
+            }
+            err := tx.Unscoped().Model(&models.Task{}).
+                Where("category_id = ?", category.ID).
+                Update("category_id", target.ID).Error
+            if err != nil {
+                return err
+            }
+            for _, before := range moved {
+                after := before
+                after.CategoryID = target.ID
+                if err := services.RecordTaskChanges(tx, before, after, actorID(c)); err != nil {
+                    return err
+                }
+                err := services.RecordTaskEvent(tx, models.EventTaskUpdated, after, actorID(c), map[string]interface{}{
+                    "previous_category_id": category.ID,
+                })
+                if err != nil {
+                    return err
+                }
+            }
+        }
+        if err := tx.Model(&category).Association("Users").Clear(); err != nil {
+            return err
+        }
+        if err := tx.Delete(&category).Error; err != nil {
+            return err
+        }
+        changes, err := services.AuditDiff(category, nil)
+        if err != nil {
+            return err
+        }
+        if target.ID != 0 {
+            // Task yang dipindah tidak dicatat satu per satu, cukup target dan jumlahnya
+            changes["reassigned_to"] = models.AuditChange{To: target.ID}
+            changes["reassigned_tasks"] = models.AuditChange{To: taskCount}
+        }
+        if err := services.RecordAuditChanges(tx, auditContext(c), models.AuditCategoryDelete, models.AggregateCategory, category.ID, changes); err != nil {
+            return err
+        }
+        return recordCategoryEvent(tx, c, models.EventCategoryDeleted, category, map[string]interface{}{
+            "reassigned_to":    target.ID,
+            "reassigned_tasks": taskCount,
+        })
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to delete category",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Category deleted successfully",
+        "reassigned_tasks": taskCount,
+    })
+}
+
+// recordCategoryEvent menulis event kategori ke outbox; kategori private dimiliki user yang login
+func recordCategoryEvent(tx *gorm.DB, c *gin.Context, eventType string, category models.Category, extra map[string]interface{}) error {
+    payload := map[string]interface{}{
+        "category": gin.H{
+            "id":          category.ID,
+            "name":        category.Name,
+            "slug":        category.Slug,
+            "color":       category.Color,
+            "description": category.Description,
+            "is_private":  category.IsPrivate,
+        },
+    }
+    for key, value := range extra {
+        payload[key] = value
+    }
+
+    event := models.OutboxEvent{
+        AggregateType: models.AggregateCategory,
+        AggregateID:   category.ID,
+        EventType:     eventType,
+        ActorID:       actorID(c),
+    }
+    if category.IsPrivate {
+        event.UserID = actorID(c)
+    }
+    return services.RecordEvent(tx, event, payload)
+}
+
+// accessibleCategories - kategori global ditambah kategori private milik user
+func accessibleCategories(userID uint) *gorm.DB {
+    owned := config.DB.Table("user_categories").Select("category_id").Where("user_id = ?", userID)
+    return config.DB.Model(&models.Category{}).Where("is_private = ? OR id IN (?)", false, owned)
+}
+
+func categoryAccessible(categoryID uint, userID uint) bool {
+    var count int64
+    accessibleCategories(userID).Where("id = ?", categoryID).Count(&count)
+    return count > 0
+}
+
+// findManageableCategory - kategori global hanya bisa diubah admin, kategori private oleh pemiliknya
+func findManageableCategory(c *gin.Context) (models.Category, bool) {
+    user, _ := middleware.CurrentUser(c)
+
+    var category models.Category
+    categoryID, ok := parseIDParam(c, "id", "category")
+    if !ok {
+        return category, false
+    }
+    if err := accessibleCategories(user.ID).First(&category, categoryID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Category not found",
+        })
+        return category, false
+    }
+
+    if !category.IsPrivate && !user.Can(models.PermissionManageCategories) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error": "Only admins can manage global categories",
+        })
+        return category, false
+    }
+
+    return category, true
+}
+
+// slugBase membuat slug dasar dari nama kategori
+func slugBase(name string) (string, error) {
+    base := strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+    if base == "" {
+        return "", fmt.Errorf("name must contain at least one letter or digit")
+    }
+    return base, nil
+}
+
+// uniqueSlug menambahkan -2, -3, dst ke slug dasar jika sudah dipakai kategori lain
+func uniqueSlug(base string, excludeID uint) (string, error) {
+    slug := base
+    for i := 2; ; i++ {
+        var count int64
+        if err := config.DB.Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+            return "", err
+        }
+        if count == 0 {
+            return slug, nil
+        }
+        slug = fmt.Sprintf("%s-%d", base, i)
+    }
+}
+
+// withUniqueSlug menjalankan save dengan slug unik. Cek slug dan insert tidak atomik, jadi jika
+// request lain keburu memakai slug yang sama (unique violation) slug dihitung ulang dan save diulang.
+func withUniqueSlug(base string, excludeID uint, save func(slug string) error) error {
+    var err error
+    for attempt := 0; attempt < maxSlugAttempts; attempt++ {
+        var slug string
+        slug, err = uniqueSlug(base, excludeID)
+        if err != nil {
+            return err
+        }
+        err = save(slug)
+        if !errors.Is(err, gorm.ErrDuplicatedKey) {
+            return err
+        }
+    }
+    return err
+}
@@ -0,0 +1,397 @@
+package controllers
+
+import (
+    "net/http"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+func GetChecklist(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionReadAllData)
+    if !ok {
+        return
+    }
+
+    var items []models.ChecklistItem
+    config.DB.Scopes(orderChecklist).Where("task_id = ?", task.ID).Find(&items)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": items,
+        "count": len(items),
+        "progress": task.Progress,
+    })
+}
+
+func AddChecklistItem(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+
+    var req models.CreateChecklistItemRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    item := models.ChecklistItem{
+        TaskID: task.ID,
+        Title:  req.Title,
+    }
+
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := lockChecklist(tx, task.ID); err != nil {
+            return err
+        }
+        var count int64
+        if err := tx.Model(&models.ChecklistItem{}).Where("task_id = ?", task.ID).Count(&count).Error; err != nil {
+            return err
+        }
+
+        // Default ditaruh di akhir; jika position diisi, item setelahnya digeser
+        item.Position = int(count)
+        if req.Position != nil && *req.Position >= 0 && *req.Position < int(count) {
+            item.Position = *req.Position
+            err := tx.Model(&models.ChecklistItem{}).
+                Where("task_id = ? AND position >= ?", task.ID, item.Position).
+                Update("position", gorm.Expr("position + 1")).Error
+            if err != nil {
+                return err
+            }
+        }
+
+        if err := tx.Create(&item).Error; err != nil {
+            return err
+        }
+        if err := recalculateTaskProgress(tx, task.ID); err != nil {
+            return err
+        }
+        return recordChecklistChange(tx, c, models.AuditTaskChecklistAdd, task.ID, item.ID, nil, item)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to add checklist item",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "message": "Checklist item added successfully",
+        "data": item,
+    })
+}
+
+func UpdateChecklistItem(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+
+    item, ok := findChecklistItem(c, task.ID)
+    if !ok {
+        return
+    }
+
+    var req models.UpdateChecklistItemRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    before := item
+    if req.Title != "" {
+        item.Title = req.Title
+    }
+    if req.Completed != nil {
+        setChecklistItemCompleted(&item, *req.Completed)
+    }
+
+    saveChecklistItem(c, before, item, "Checklist item updated successfully")
+}
+
+func ToggleChecklistItem(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+
+    item, ok := findChecklistItem(c, task.ID)
+    if !ok {
+        return
+    }
+
+    before := item
+    setChecklistItemCompleted(&item, !item.Completed)
+    saveChecklistItem(c, before, item, "Checklist item toggled successfully")
+}
+
+// ReorderChecklist menyusun ulang posisi item sesuai urutan item_ids (harus berisi semua item task)
+func ReorderChecklist(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+
+    var req models.ReorderChecklistRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    var items []models.ChecklistItem
+    if err := config.DB.Scopes(orderChecklist).Where("task_id = ?", task.ID).Find(&items).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to reorder checklist",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    existing := make(map[uint]bool, len(items))
+    previousOrder := make([]uint, len(items))
+    for i, item := range items {
+        existing[item.ID] = true
+        previousOrder[i] = item.ID
+    }
+    seen := make(map[uint]bool, len(req.ItemIDs))
+    for _, id := range req.ItemIDs {
+        if !existing[id] || seen[id] {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid item order",
+                "details": "item_ids must list every checklist item of the task exactly once",
+            })
+            return
+        }
+        seen[id] = true
+    }
+    if len(seen) != len(existing) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid item order",
+            "details": "item_ids must list every checklist item of the task exactly once",
+        })
+        return
+    }
+
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        for position, id := range req.ItemIDs {
+            if err := tx.Model(&models.ChecklistItem{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+                return err
+            }
+        }
+        return recordTaskActivity(tx, c, models.AuditTaskChecklistReorder, task.ID, map[string]models.AuditChange{
+            "checklist_order": {From: previousOrder, To: req.ItemIDs},
+        }, nil)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to reorder checklist",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    config.DB.Scopes(orderChecklist).Where("task_id = ?", task.ID).Find(&items)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Checklist reordered successfully",
+        "data": items,
+    })
+}
+
+func DeleteChecklistItem(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+
+    item, ok := findChecklistItem(c, task.ID)
+    if !ok {
+        return
+    }
+
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := lockChecklist(tx, task.ID); err != nil {
+            return err
+        }
+        if err := tx.Delete(&item).Error; err != nil {
+            return err
+        }
+        err := tx.Model(&models.ChecklistItem{}).
+            Where("task_id = ? AND position > ?", task.ID, item.Position).
+            Update("position", gorm.Expr("position - 1")).Error
+        if err != nil {
+            return err
+        }
+        if err := recalculateTaskProgress(tx, task.ID); err != nil {
+            return err
+        }
+        return recordChecklistChange(tx, c, models.AuditTaskChecklistDelete, task.ID, item.ID, item, nil)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to delete checklist item",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Checklist item deleted successfully",
+    })
+}
+
+// lockChecklist mengunci baris task sampai transaksi selesai, supaya penambahan / penghapusan item yang
+// berjalan bersamaan tidak menghitung posisi dari jumlah item yang sama
+func lockChecklist(tx *gorm.DB, taskID uint) error {
+    var task models.Task
+    return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&task, taskID).Error
+}
+
+func orderChecklist(db *gorm.DB) *gorm.DB {
+    return db.Order("position ASC, id ASC")
+}
+
+func findAuthorizedTask(c *gin.Context, permission string) (models.Task, bool) {
+    var task models.Task
+    taskID, ok := parseIDParam(c, "id", "task")
+    if !ok {
+        return task, false
+    }
+    if err := config.DB.First(&task, taskID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Task not found",
+        })
+        return task, false
+    }
+
+    if !authorizeTask(c, task, permission) {
+        return task, false
+    }
+    return task, true
+}
+
+func findChecklistItem(c *gin.Context, taskID uint) (models.ChecklistItem, bool) {
+    var item models.ChecklistItem
+    itemID, ok := parseIDParam(c, "item_id", "checklist item")
+    if !ok {
+        return item, false
+    }
+    if err := config.DB.Where("task_id = ?", taskID).First(&item, itemID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Checklist item not found",
+        })
+        return item, false
+    }
+    return item, true
+}
+
+func setChecklistItemCompleted(item *models.ChecklistItem, completed bool) {
+    if item.Completed == completed {
+        return
+    }
+    item.Completed = completed
+    if completed {
+        now := time.Now()
+        item.CompletedAt = &now
+    } else {
+        item.CompletedAt = nil
+    }
+}
+
+func saveChecklistItem(c *gin.Context, before, item models.ChecklistItem, message string) {
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&item).Error; err != nil {
+            return err
+        }
+        if err := recalculateTaskProgress(tx, item.TaskID); err != nil {
+            return err
+        }
+        return recordChecklistChange(tx, c, models.AuditTaskChecklistUpdate, item.TaskID, item.ID, before, item)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update checklist item",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": message,
+        "data": item,
+    })
+}
+
+// recordChecklistChange - audit dan event untuk satu item checklist; item_id ikut dicatat karena entity audit-nya task
+func recordChecklistChange(tx *gorm.DB, c *gin.Context, action string, taskID, itemID uint, before, after interface{}) error {
+    changes, err := services.AuditDiff(before, after)
+    if err != nil {
+        return err
+    }
+    changes["checklist_item_id"] = models.AuditChange{To: itemID}
+    return recordTaskActivity(tx, c, action, taskID, changes, map[string]interface{}{"checklist_item_id": itemID})
+}
+
+// recordTaskActivity mencatat perubahan checklist / reminder sebagai audit task dan event task.updated
+// di transaksi yang sama, supaya SSE, board dan webhook ikut diperbarui. Task dimuat ulang karena
+// progress-nya mungkin baru dihitung ulang.
+func recordTaskActivity(tx *gorm.DB, c *gin.Context, action string, taskID uint, changes map[string]models.AuditChange, extra map[string]interface{}) error {
+    if err := services.RecordAuditChanges(tx, auditContext(c), action, models.AggregateTask, taskID, changes); err != nil {
+        return err
+    }
+
+    var task models.Task
+    if err := tx.First(&task, taskID).Error; err != nil {
+        return err
+    }
+    payload := map[string]interface{}{"change": action}
+    for key, value := range extra {
+        payload[key] = value
+    }
+    return services.RecordTaskEvent(tx, models.EventTaskUpdated, task, actorID(c), payload)
+}
+
+// recalculateTaskProgress menyimpan persentase item checklist yang selesai ke task
+func recalculateTaskProgress(tx *gorm.DB, taskID uint) error {
+    var total, completed int64
+    tx.Model(&models.ChecklistItem{}).Where("task_id = ?", taskID).Count(&total)
+    tx.Model(&models.ChecklistItem{}).Where("task_id = ? AND completed = ?", taskID, true).Count(&completed)
+
+    progress := 0
+    if total > 0 {
+        progress = int(completed * 100 / total)
+    }
+    return tx.Model(&models.Task{}).Where("id = ?", taskID).UpdateColumn("progress", progress).Error
+}
+
+// completeChecklist menandai semua item selesai ketika task-nya ditandai done
+func completeChecklist(tx *gorm.DB, taskID uint) error {
+    err := tx.Model(&models.ChecklistItem{}).
+        Where("task_id = ? AND completed = ?", taskID, false).
+        Updates(map[string]interface{}{"completed": true, "completed_at": time.Now()}).Error
+    if err != nil {
+        return err
+    }
+    return recalculateTaskProgress(tx, taskID)
+}
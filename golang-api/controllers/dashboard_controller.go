@@ -3,9 +3,11 @@ package controllers
 import (
     "net/http"
     "taskflow-api/config"
+    "taskflow-api/middleware"
     "taskflow-api/models"
     
     "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
 )
 
 type DashboardStats struct {
@@ -30,12 +32,25 @@ type TasksByStatus struct {
 func GetDashboardStats(c *gin.Context) {
     var stats DashboardStats
     
+    // Admin & auditor melihat statistik seluruh user, member hanya datanya sendiri
+    user, _ := middleware.CurrentUser(c)
+    scope := func(db *gorm.DB) *gorm.DB {
+        if user.Can(models.PermissionReadAllData) {
+            return db
+        }
+        return db.Where("tasks.user_id = ?", user.ID)
+    }
+    
     // Get basic counts
-    config.DB.Model(&models.User{}).Count(&stats.TotalUsers)
-    config.DB.Model(&models.Task{}).Count(&stats.TotalTasks)
-    config.DB.Model(&models.Task{}).Where("status = ?", "done").Count(&stats.CompletedTasks)
-    config.DB.Model(&models.Task{}).Where("status = ?", "todo").Count(&stats.PendingTasks)
-    config.DB.Model(&models.Task{}).Where("status = ?", "in_progress").Count(&stats.InProgressTasks)
+    if user.Can(models.PermissionReadAllData) {
+        config.DB.Model(&models.User{}).Count(&stats.TotalUsers)
+    } else {
+        stats.TotalUsers = 1
+    }
+    config.DB.Model(&models.Task{}).Scopes(scope).Count(&stats.TotalTasks)
+    config.DB.Model(&models.Task{}).Scopes(scope).Where("status = ?", "done").Count(&stats.CompletedTasks)
+    config.DB.Model(&models.Task{}).Scopes(scope).Where("status = ?", "todo").Count(&stats.PendingTasks)
+    config.DB.Model(&models.Task{}).Scopes(scope).Where("status = ?", "in_progress").Count(&stats.InProgressTasks)
     
     // Calculate completion rate
     if stats.TotalTasks > 0 {
@@ -45,6 +60,7 @@ func GetDashboardStats(c *gin.Context) {
     // Get tasks by category
     var tasksByCategory []TasksByCategory
     config.DB.Table("tasks").
+        Scopes(scope).
         Select("categories.name as category_name, COUNT(tasks.id) as task_count").
         Joins("JOIN categories ON categories.id = tasks.category_id").
         Where("tasks.deleted_at IS NULL").
@@ -54,6 +70,7 @@ func GetDashboardStats(c *gin.Context) {
     // Get tasks by status
     var tasksByStatus []TasksByStatus
     config.DB.Table("tasks").
+        Scopes(scope).
         Select("status, COUNT(id) as task_count").
         Where("deleted_at IS NULL").
         Group("status").
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+    "net/http"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+
+    "github.com/gin-gonic/gin"
+)
+
+func GetMyDevices(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    var devices []models.DeviceToken
+    config.DB.Where("user_id = ?", user.ID).Order("last_seen_at DESC").Find(&devices)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": devices,
+        "count": len(devices),
+    })
+}
+
+// RegisterMyDevice mendaftarkan token FCM device; dipanggil ulang setiap app dibuka untuk memperbarui last_seen
+func RegisterMyDevice(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    var req models.RegisterDeviceRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    device, err := services.NewDeviceTokenService().RegisterDevice(user.ID, req.Token, req.Platform, c.Request.UserAgent())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to register device",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Device registered successfully",
+        "data": device,
+    })
+}
+
+// UnregisterMyDevice menghapus device berdasarkan token, mis. saat logout dari device tersebut
+func UnregisterMyDevice(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    var req models.UnregisterDeviceRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    removed, err := services.NewDeviceTokenService().UnregisterDevice(user.ID, req.Token)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to unregister device",
+            "details": err.Error(),
+        })
+        return
+    }
+    if !removed {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Device not found",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Device unregistered successfully",
+    })
+}
+
+func DeleteMyDevice(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    result := config.DB.Where("user_id = ? AND id = ?", user.ID, c.Param("id")).Delete(&models.DeviceToken{})
+    if result.Error != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to unregister device",
+            "details": result.Error.Error(),
+        })
+        return
+    }
+    if result.RowsAffected == 0 {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Device not found",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Device unregistered successfully",
+    })
+}
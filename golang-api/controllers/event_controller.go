@@ -0,0 +1,71 @@
+package controllers
+
+import (
+    "fmt"
+    "net/http"
+    "taskflow-api/middleware"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// eventHeartbeat - komentar ping supaya proxy tidak menutup koneksi yang idle
+const eventHeartbeat = 25 * time.Second
+
+// StreamMyEvents - Server-Sent Events untuk perubahan task dan notifikasi baru milik user.
+// Client yang reconnect mengirim Last-Event-ID (atau ?last_event_id=) untuk menerima event yang terlewat.
+func StreamMyEvents(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    lastEventID := c.GetHeader("Last-Event-ID")
+    if lastEventID == "" {
+        lastEventID = c.Query("last_event_id")
+    }
+
+    broker := services.Events()
+    subscription, backlog, ok := broker.Subscribe(user.ID, lastEventID)
+    if !ok {
+        c.JSON(http.StatusServiceUnavailable, gin.H{
+            "error": "Server is shutting down",
+        })
+        return
+    }
+    defer broker.Unsubscribe(subscription)
+
+    c.Header("Content-Type", "text/event-stream")
+    c.Header("Cache-Control", "no-cache")
+    c.Header("Connection", "keep-alive")
+    c.Header("X-Accel-Buffering", "no") // nginx: jangan buffer stream
+    c.Status(http.StatusOK)
+
+    fmt.Fprint(c.Writer, "retry: 3000\n\n")
+    for _, event := range backlog {
+        writeLiveEvent(c, event)
+    }
+    c.Writer.Flush()
+
+    heartbeat := time.NewTicker(eventHeartbeat)
+    defer heartbeat.Stop()
+
+    for {
+        select {
+        case <-c.Request.Context().Done():
+            return
+        case event, open := <-subscription.Events():
+            if !open {
+                // Broker ditutup (shutdown) atau client terlalu lambat; client akan reconnect
+                return
+            }
+            writeLiveEvent(c, event)
+            c.Writer.Flush()
+        case <-heartbeat.C:
+            fmt.Fprintf(c.Writer, ": ping %d\n\n", time.Now().Unix())
+            c.Writer.Flush()
+        }
+    }
+}
+
+func writeLiveEvent(c *gin.Context, event services.LiveEvent) {
+    fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
@@ -13,11 +13,19 @@ import (
 )
 
 func ExportUserTasks(c *gin.Context) {
-    userID := c.Param("user_id")
+    userID, ok := authorizeUserParam(c, c.Param("user_id"), models.PermissionReadAllData)
+    if !ok {
+        return
+    }
+    
+    exportTasksCSV(c, userID)
+}
 
+func exportTasksCSV(c *gin.Context, userID uint) {
     var tasks []models.Task
     result := config.DB.Where("user_id = ?", userID).
         Preload("Category").
+        Preload("ChecklistItems", orderChecklist).
         Find(&tasks)
 
     if result.Error != nil {
@@ -27,22 +35,26 @@ func ExportUserTasks(c *gin.Context) {
         return
     }
 
+    // Semua timestamp ditampilkan dalam zona waktu pemilik task
+    loc := exportLocation(userID)
+    now := time.Now().In(loc)
+
     // Set headers for CSV download
     c.Header("Content-Type", "text/csv")
-    c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=my_tasks_%s.csv", time.Now().Format("2006-01-02")))
+    c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=my_tasks_%s.csv", now.Format("2006-01-02")))
 
     writer := csv.NewWriter(c.Writer)
     defer writer.Flush()
 
     // Write CSV headers
-    headers := []string{"ID", "Title", "Description", "Status", "Priority", "Category", "Deadline", "Created Date"}
+    headers := []string{"ID", "Title", "Description", "Status", "Priority", "Category", "Deadline", "Created Date", "Type", "Parent Task ID", "Progress"}
     writer.Write(headers)
 
     // Write task data
     for _, task := range tasks {
         deadline := ""
         if task.Deadline != nil {
-            deadline = task.Deadline.Format("2006-01-02 15:04:05")
+            deadline = task.Deadline.In(loc).Format(exportTimeLayout)
         }
 
         // Perbaikan di sini: tidak menggunakan `task.Category != nil` karena Category adalah struct
@@ -59,18 +71,50 @@ func ExportUserTasks(c *gin.Context) {
             task.Priority,
             categoryName,
             deadline,
-            task.CreatedAt.Format("2006-01-02 15:04:05"),
+            task.CreatedAt.In(loc).Format(exportTimeLayout),
+            "task",
+            "",
+            strconv.Itoa(task.Progress),
         }
         writer.Write(record)
+
+        // Item checklist ditulis tepat di bawah task induknya
+        for _, item := range task.ChecklistItems {
+            status := "todo"
+            if item.Completed {
+                status = "done"
+            }
+            writer.Write([]string{
+                strconv.Itoa(int(item.ID)),
+                item.Title,
+                "",
+                status,
+                "",
+                "",
+                "",
+                item.CreatedAt.In(loc).Format(exportTimeLayout),
+                "checklist_item",
+                strconv.Itoa(int(task.ID)),
+                "",
+            })
+        }
     }
 }
 
 func ExportUserTasksJSON(c *gin.Context) {
-    userID := c.Param("user_id")
+    userID, ok := authorizeUserParam(c, c.Param("user_id"), models.PermissionReadAllData)
+    if !ok {
+        return
+    }
+    
+    exportTasksJSON(c, userID)
+}
 
+func exportTasksJSON(c *gin.Context, userID uint) {
     var tasks []models.Task
     result := config.DB.Where("user_id = ?", userID).
         Preload("Category").
+        Preload("ChecklistItems", orderChecklist).
         Find(&tasks)
 
     if result.Error != nil {
@@ -80,14 +124,57 @@ func ExportUserTasksJSON(c *gin.Context) {
         return
     }
 
+    // Semua timestamp ditampilkan dalam zona waktu pemilik task
+    loc := exportLocation(userID)
+    now := time.Now().In(loc)
+
     // Set headers for JSON download
     c.Header("Content-Type", "application/json")
-    c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=my_tasks_%s.json", time.Now().Format("2006-01-02")))
+    c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=my_tasks_%s.json", now.Format("2006-01-02")))
+
+    for i := range tasks {
+        localizeTask(&tasks[i], loc)
+    }
 
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data": tasks,
-        "exported_at": time.Now(),
+        "exported_at": now,
+        "timezone": loc.String(),
         "total_tasks": len(tasks),
     })
 }
+
+// exportTimeLayout - zona ditulis eksplisit karena waktu mengikuti timezone user
+const exportTimeLayout = "2006-01-02 15:04:05 MST"
+
+func exportLocation(userID uint) *time.Location {
+    var owner models.User
+    if err := config.DB.Select("id", "timezone").First(&owner, userID).Error; err != nil {
+        return time.UTC
+    }
+    return owner.Location()
+}
+
+func localizeTask(task *models.Task, loc *time.Location) {
+    localize := func(t *time.Time) *time.Time {
+        if t == nil {
+            return nil
+        }
+        local := t.In(loc)
+        return &local
+    }
+
+    task.CreatedAt = task.CreatedAt.In(loc)
+    task.UpdatedAt = task.UpdatedAt.In(loc)
+    task.Deadline = localize(task.Deadline)
+    task.ReminderSentAt = localize(task.ReminderSentAt)
+    task.Category.CreatedAt = task.Category.CreatedAt.In(loc)
+    task.Category.UpdatedAt = task.Category.UpdatedAt.In(loc)
+    for i := range task.ChecklistItems {
+        item := &task.ChecklistItems[i]
+        item.CreatedAt = item.CreatedAt.In(loc)
+        item.UpdatedAt = item.UpdatedAt.In(loc)
+        item.CompletedAt = localize(item.CompletedAt)
+    }
+}
@@ -0,0 +1,308 @@
+package controllers
+
+import (
+    "fmt"
+    "net/http"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
+)
+
+// Endpoint /api/me - user selalu diambil dari identitas yang sudah diautentikasi
+
+func GetMe(c *gin.Context) {
+    current, _ := middleware.CurrentUser(c)
+
+    var user models.User
+    result := config.DB.Preload("Categories").First(&user, current.ID)
+    if result.Error != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "User not found",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": user,
+    })
+}
+
+func GetMyTasks(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+    listTasks(c, user.ID)
+}
+
+func CreateMyTask(c *gin.Context) {
+    var req models.CreateTaskRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    // user_id dari client diabaikan
+    user, _ := middleware.CurrentUser(c)
+    req.UserID = user.ID
+
+    createTask(c, req)
+}
+
+func GetMyTask(c *gin.Context) {
+    task, ok := findMyTask(c)
+    if !ok {
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": task,
+    })
+}
+
+func UpdateMyTask(c *gin.Context) {
+    task, ok := findMyTask(c)
+    if !ok {
+        return
+    }
+
+    updateTask(c, task)
+}
+
+func DeleteMyTask(c *gin.Context) {
+    task, ok := findMyTask(c)
+    if !ok {
+        return
+    }
+
+    deleteTask(c, task)
+}
+
+func ExportMyTasks(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    switch c.DefaultQuery("format", "csv") {
+    case "csv":
+        exportTasksCSV(c, user.ID)
+    case "json":
+        exportTasksJSON(c, user.ID)
+    default:
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Unsupported export format",
+            "details": "format must be csv or json",
+        })
+    }
+}
+
+// findMyTask mencari task milik user yang login; task milik user lain dianggap tidak ada
+func findMyTask(c *gin.Context) (models.Task, bool) {
+    user, _ := middleware.CurrentUser(c)
+
+    var task models.Task
+    taskID, ok := parseIDParam(c, "id", "task")
+    if !ok {
+        return task, false
+    }
+    result := config.DB.Preload("Category").
+        Preload("ChecklistItems", orderChecklist).
+        Where("user_id = ?", user.ID).
+        First(&task, taskID)
+    if result.Error != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Task not found",
+        })
+        return task, false
+    }
+    return task, true
+}
+
+func GetMyNotificationSettings(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": notificationSettings(user),
+    })
+}
+
+// UpdateMyNotificationSettings mengatur timezone, jam tenang dan hari do-not-disturb user
+func UpdateMyNotificationSettings(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    var req models.UpdateNotificationSettingsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    before := user
+    if req.Timezone != nil {
+        if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid timezone",
+                "details": "timezone must be an IANA name like Asia/Jakarta",
+            })
+            return
+        }
+        user.Timezone = *req.Timezone
+    }
+    if req.Locale != nil {
+        user.Locale = *req.Locale
+    }
+    if req.QuietHoursStart != nil {
+        user.QuietHoursStart = *req.QuietHoursStart
+    }
+    if req.QuietHoursEnd != nil {
+        user.QuietHoursEnd = *req.QuietHoursEnd
+    }
+    if req.DoNotDisturbDays != nil {
+        // Semua hari DND berarti notifikasi tidak pernah bisa dikirim dan terus ditunda
+        if models.CoversEveryWeekday(*req.DoNotDisturbDays) {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid do-not-disturb days",
+                "details": "do_not_disturb_days must leave at least one day free",
+            })
+            return
+        }
+        user.DoNotDisturbDays = *req.DoNotDisturbDays
+    }
+    if req.StatusNotifications != nil {
+        user.StatusNotifications = *req.StatusNotifications
+    }
+    if req.DigestFrequency != nil {
+        user.DigestFrequency = *req.DigestFrequency
+    }
+    if req.DigestTime != nil {
+        if _, ok := models.ParseClock(*req.DigestTime); !ok {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid digest time",
+                "details": "digest_time must be HH:MM",
+            })
+            return
+        }
+        user.DigestTime = *req.DigestTime
+    }
+    if req.DigestWeekday != nil {
+        user.DigestWeekday = *req.DigestWeekday
+    }
+    if req.WebhookURL != nil {
+        if *req.WebhookURL != "" && !validateOutboundURL(c, "webhook_url", *req.WebhookURL) {
+            return
+        }
+        user.NotificationWebhookURL = *req.WebhookURL
+    }
+    if len(req.Channels) > 0 {
+        channels := make(map[string][]string, len(user.NotificationChannels)+len(req.Channels))
+        for notificationType, selected := range user.NotificationChannels {
+            channels[notificationType] = selected
+        }
+        for notificationType, selected := range req.Channels {
+            if !models.IsNotificationType(notificationType) {
+                c.JSON(http.StatusBadRequest, gin.H{
+                    "error": "Invalid notification channels",
+                    "details": fmt.Sprintf("unknown notification type %q", notificationType),
+                })
+                return
+            }
+            for _, channel := range selected {
+                if !models.IsNotificationChannel(channel) {
+                    c.JSON(http.StatusBadRequest, gin.H{
+                        "error": "Invalid notification channels",
+                        "details": fmt.Sprintf("unknown channel %q", channel),
+                    })
+                    return
+                }
+            }
+            channels[notificationType] = uniqueStrings(selected)
+        }
+        user.NotificationChannels = channels
+    }
+
+    // Jam tenang harus diisi keduanya (HH:MM) atau dikosongkan keduanya
+    _, okStart := models.ParseClock(user.QuietHoursStart)
+    _, okEnd := models.ParseClock(user.QuietHoursEnd)
+    quietHoursEmpty := user.QuietHoursStart == "" && user.QuietHoursEnd == ""
+    if !quietHoursEmpty && (!okStart || !okEnd) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid quiet hours",
+            "details": "quiet_hours_start and quiet_hours_end must both be HH:MM, or both empty",
+        })
+        return
+    }
+
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        err := tx.Model(&user).
+            Select("timezone", "locale", "quiet_hours_start", "quiet_hours_end", "do_not_disturb_days", "status_notifications",
+                "notification_channels", "notification_webhook_url", "digest_frequency", "digest_time", "digest_weekday").
+            Updates(user).Error
+        if err != nil {
+            return err
+        }
+        return services.RecordAudit(tx, auditContext(c), models.AuditUserSettingsUpdate, models.AggregateUser, user.ID, before, user)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update notification settings",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Notification settings updated successfully",
+        "data": notificationSettings(user),
+    })
+}
+
+func notificationSettings(user models.User) gin.H {
+    days := user.DoNotDisturbDays
+    if days == nil {
+        days = []string{}
+    }
+    statuses := user.StatusNotifications
+    if statuses == nil {
+        statuses = models.DefaultStatusNotifications
+    }
+    channels := make(map[string][]string, len(models.NotificationTypes))
+    for _, notificationType := range models.NotificationTypes {
+        channels[notificationType] = user.ChannelsFor(notificationType)
+    }
+    return gin.H{
+        "timezone": user.Location().String(),
+        "locale": services.ResolveLocale(user.Locale),
+        "available_locales": models.SupportedLocales,
+        "quiet_hours_start": user.QuietHoursStart,
+        "quiet_hours_end": user.QuietHoursEnd,
+        "do_not_disturb_days": days,
+        "status_notifications": statuses,
+        "channels": channels,
+        "webhook_url": user.NotificationWebhookURL,
+        "digest_frequency": user.DigestFrequency,
+        "digest_time": user.DigestTime,
+        "digest_weekday": user.DigestWeekday,
+        "available_channels": services.Notifications().Channels(),
+        "in_quiet_hours": user.InQuietHours(time.Now()),
+    }
+}
+
+func uniqueStrings(values []string) []string {
+    seen := make(map[string]bool, len(values))
+    result := []string{}
+    for _, value := range values {
+        if !seen[value] {
+            seen[value] = true
+            result = append(result, value)
+        }
+    }
+    return result
+}
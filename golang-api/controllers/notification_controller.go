@@ -0,0 +1,156 @@
+package controllers
+
+import (
+    "net/http"
+    "strconv"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+const (
+    defaultNotificationLimit = 20
+    maxNotificationLimit     = 100
+)
+
+// GetMyNotifications - inbox user, terbaru dulu. Pagination pakai ?limit= dan ?cursor= (next_cursor dari halaman sebelumnya)
+func GetMyNotifications(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    limit := defaultNotificationLimit
+    if value := c.Query("limit"); value != "" {
+        n, err := strconv.Atoi(value)
+        if err != nil || n < 1 || n > maxNotificationLimit {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "limit must be between 1 and 100",
+            })
+            return
+        }
+        limit = n
+    }
+
+    query := config.DB.Where("user_id = ?", user.ID)
+    if value := c.Query("cursor"); value != "" {
+        cursor, err := strconv.ParseUint(value, 10, 64)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "invalid cursor",
+            })
+            return
+        }
+        query = query.Where("id < ?", cursor)
+    }
+    if c.Query("unread") == "true" {
+        query = query.Where("read_at IS NULL")
+    }
+
+    var notifications []models.Notification
+    if err := query.Order("id DESC").Limit(limit + 1).Find(&notifications).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch notifications",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    hasMore := len(notifications) > limit
+    if hasMore {
+        notifications = notifications[:limit]
+    }
+
+    pagination := gin.H{
+        "limit":       limit,
+        "has_more":    hasMore,
+        "next_cursor": nil,
+    }
+    if hasMore {
+        pagination["next_cursor"] = strconv.FormatUint(uint64(notifications[len(notifications)-1].ID), 10)
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": notifications,
+        "count": len(notifications),
+        "unread_count": unreadNotificationCount(user.ID),
+        "pagination": pagination,
+    })
+}
+
+func GetMyUnreadNotificationCount(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": gin.H{
+            "unread_count": unreadNotificationCount(user.ID),
+        },
+    })
+}
+
+func MarkNotificationRead(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    notificationID, ok := parseIDParam(c, "id", "notification")
+    if !ok {
+        return
+    }
+
+    var notification models.Notification
+    if err := config.DB.Where("user_id = ?", user.ID).First(&notification, notificationID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Notification not found",
+        })
+        return
+    }
+
+    if notification.ReadAt == nil {
+        now := time.Now()
+        notification.ReadAt = &now
+        if err := config.DB.Model(&notification).Update("read_at", now).Error; err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": "Failed to update notification",
+                "details": err.Error(),
+            })
+            return
+        }
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Notification marked as read",
+        "data": notification,
+        "unread_count": unreadNotificationCount(user.ID),
+    })
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    result := config.DB.Model(&models.Notification{}).
+        Where("user_id = ? AND read_at IS NULL", user.ID).
+        Update("read_at", time.Now())
+    if result.Error != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update notifications",
+            "details": result.Error.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "All notifications marked as read",
+        "marked": result.RowsAffected,
+    })
+}
+
+func unreadNotificationCount(userID uint) int64 {
+    var count int64
+    config.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count)
+    return count
+}
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+    "io"
+    "net/http"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
+)
+
+func GetTaskReminders(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionReadAllData)
+    if !ok {
+        return
+    }
+
+    var reminders []models.TaskReminder
+    config.DB.Where("task_id = ?", task.ID).Order("remind_at ASC").Find(&reminders)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": reminders,
+        "count": len(reminders),
+    })
+}
+
+// UpdateTaskReminders mengganti jadwal reminder task; offsets kosong mematikan semua reminder
+func UpdateTaskReminders(c *gin.Context) {
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+
+    var req models.UpdateRemindersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if task.Deadline == nil && len(req.Offsets) > 0 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Task has no deadline",
+            "details": "set a deadline before scheduling reminders",
+        })
+        return
+    }
+
+    reminderService := services.NewReminderService()
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        previous := reminderService.TaskOffsets(tx, task.ID)
+        if err := reminderService.SetTaskReminders(tx, task, req.Offsets); err != nil {
+            return err
+        }
+        return recordTaskActivity(tx, c, models.AuditTaskReminderUpdate, task.ID, map[string]models.AuditChange{
+            "reminder_offsets": {From: previous, To: reminderService.TaskOffsets(tx, task.ID)},
+        }, nil)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update reminders",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    var reminders []models.TaskReminder
+    config.DB.Where("task_id = ?", task.ID).Order("remind_at ASC").Find(&reminders)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Reminders updated successfully",
+        "data": reminders,
+    })
+}
+
+func GetMyReminderDefaults(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": gin.H{
+            "offsets": services.NewReminderService().OffsetsForUser(user),
+        },
+    })
+}
+
+// UpdateMyReminderDefaults mengatur offset default untuk task baru; offsets kosong kembali ke default aplikasi
+func UpdateMyReminderDefaults(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    var req models.UpdateRemindersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    offsets := services.NormalizeOffsets(req.Offsets)
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        // Select supaya slice kosong (reset ke default aplikasi) tetap tersimpan
+        if err := tx.Model(&user).Select("reminder_offsets").Updates(models.User{ReminderOffsets: offsets}).Error; err != nil {
+            return err
+        }
+        return services.RecordAudit(tx, auditContext(c), models.AuditUserSettingsUpdate, models.AggregateUser, user.ID,
+            gin.H{"reminder_offsets": user.ReminderOffsets}, gin.H{"reminder_offsets": offsets})
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update reminder defaults",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    user.ReminderOffsets = offsets
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Reminder defaults updated successfully",
+        "data": gin.H{
+            "offsets": services.NewReminderService().OffsetsForUser(user),
+        },
+    })
+}
+
+// SnoozeReminder menunda reminder selama minutes menit atau sampai until (default 10 menit)
+func SnoozeReminder(c *gin.Context) {
+    reminder, ok := findAuthorizedReminder(c)
+    if !ok {
+        return
+    }
+
+    var req models.SnoozeReminderRequest
+    if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if req.Minutes != 0 && req.Until != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid snooze",
+            "details": "pass either minutes or until, not both",
+        })
+        return
+    }
+
+    until := time.Now().Add(models.DefaultSnoozeMinutes * time.Minute)
+    if req.Minutes != 0 {
+        until = time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+    }
+    if req.Until != nil {
+        if !req.Until.After(time.Now()) {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid snooze",
+                "details": "until must be in the future",
+            })
+            return
+        }
+        if req.Until.After(time.Now().Add(models.MaxSnoozeMinutes * time.Minute)) {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid snooze",
+                "details": "until must be at most 7 days ahead",
+            })
+            return
+        }
+        until = *req.Until
+    }
+
+    before := reminder
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := services.NewReminderService().SnoozeReminder(tx, &reminder, until); err != nil {
+            return err
+        }
+        return recordReminderChange(tx, c, models.AuditTaskReminderSnooze, before, reminder)
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to snooze reminder",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Reminder snoozed successfully",
+        "data": reminder,
+    })
+}
+
+func AcknowledgeReminder(c *gin.Context) {
+    reminder, ok := findAuthorizedReminder(c)
+    if !ok {
+        return
+    }
+
+    if reminder.AcknowledgedAt == nil {
+        before := reminder
+        err := config.DB.Transaction(func(tx *gorm.DB) error {
+            if err := services.NewReminderService().AcknowledgeReminder(tx, &reminder); err != nil {
+                return err
+            }
+            return recordReminderChange(tx, c, models.AuditTaskReminderAcknowledge, before, reminder)
+        })
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": "Failed to acknowledge reminder",
+                "details": err.Error(),
+            })
+            return
+        }
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Reminder acknowledged successfully",
+        "data": reminder,
+    })
+}
+
+// recordReminderChange - audit dan event untuk satu reminder; reminder_id ikut dicatat karena entity audit-nya task
+func recordReminderChange(tx *gorm.DB, c *gin.Context, action string, before, after models.TaskReminder) error {
+    changes, err := services.AuditDiff(before, after)
+    if err != nil {
+        return err
+    }
+    changes["reminder_id"] = models.AuditChange{To: after.ID}
+    return recordTaskActivity(tx, c, action, after.TaskID, changes, map[string]interface{}{"reminder_id": after.ID})
+}
+
+func findAuthorizedReminder(c *gin.Context) (models.TaskReminder, bool) {
+    var reminder models.TaskReminder
+    task, ok := findAuthorizedTask(c, models.PermissionWriteAllData)
+    if !ok {
+        return reminder, false
+    }
+
+    reminderID, ok := parseIDParam(c, "reminder_id", "reminder")
+    if !ok {
+        return reminder, false
+    }
+    if err := config.DB.Where("task_id = ?", task.ID).First(&reminder, reminderID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Reminder not found",
+        })
+        return reminder, false
+    }
+    return reminder, true
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+    "html"
+    "net/http"
+    "strconv"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "unicode"
+
+    "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
+)
+
+const (
+    defaultSearchLimit = 20
+    maxSearchLimit     = 100
+
+    // Penanda highlight dari ts_headline (karakter private-use), diganti <mark> setelah teks di-escape
+    highlightStart = "\uE000"
+    highlightStop  = "\uE001"
+)
+
+type TaskSearchResult struct {
+    Task                 models.Task `json:"task"`
+    Rank                 float64     `json:"rank"`
+    TitleHighlight       string      `json:"title_highlight"`
+    DescriptionHighlight string      `json:"description_highlight"`
+}
+
+type taskSearchHit struct {
+    ID                   uint
+    Rank                 float64
+    TitleHighlight       string
+    DescriptionHighlight string
+}
+
+// SearchTasks - full-text search pada judul & deskripsi task milik user.
+// Mendukung frasa ("laporan mingguan"), prefix (lapor*) dan filter yang sama dengan listing task.
+func SearchTasks(c *gin.Context) {
+    tsQuery := buildTSQuery(c.Query("q"))
+    if tsQuery == "" {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid query parameter",
+            "details": "q must contain at least one word",
+        })
+        return
+    }
+
+    limit := defaultSearchLimit
+    if value := c.Query("limit"); value != "" {
+        l, err := strconv.Atoi(value)
+        if err != nil || l < 1 {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "limit must be a positive number",
+            })
+            return
+        }
+        if l > maxSearchLimit {
+            l = maxSearchLimit
+        }
+        limit = l
+    }
+    offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+    if offset < 0 {
+        offset = 0
+    }
+
+    // Admin / auditor boleh mencari di task user lain lewat ?user_id=
+    user, _ := middleware.CurrentUser(c)
+    userID := user.ID
+    if value := c.Query("user_id"); value != "" && user.Can(models.PermissionReadAllData) {
+        id, err := strconv.ParseUint(value, 10, 64)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid user ID",
+            })
+            return
+        }
+        userID = uint(id)
+    }
+
+    query, err := applyTaskFilters(c, config.DB.Model(&models.Task{}).
+        Where("tasks.user_id = ?", userID).
+        Where("tasks.search_vector @@ to_tsquery('simple', ?)", tsQuery))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid query parameter",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    query = query.Session(&gorm.Session{})
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to search tasks",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    titleOptions := "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true"
+    descriptionOptions := "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxWords=35, MinWords=15"
+
+    var hits []taskSearchHit
+    result := query.
+        Select(`tasks.id,
+            ts_rank_cd(tasks.search_vector, to_tsquery('simple', ?)) AS rank,
+            ts_headline('simple', tasks.title, to_tsquery('simple', ?), ?) AS title_highlight,
+            ts_headline('simple', COALESCE(tasks.description, ''), to_tsquery('simple', ?), ?) AS description_highlight`,
+            tsQuery, tsQuery, titleOptions, tsQuery, descriptionOptions).
+        Order("rank DESC, tasks.updated_at DESC").
+        Limit(limit).
+        Offset(offset).
+        Scan(&hits)
+    if result.Error != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to search tasks",
+            "details": result.Error.Error(),
+        })
+        return
+    }
+
+    results := make([]TaskSearchResult, 0, len(hits))
+    if len(hits) > 0 {
+        ids := make([]uint, len(hits))
+        for i, hit := range hits {
+            ids[i] = hit.ID
+        }
+
+        var tasks []models.Task
+        config.DB.Preload("Category").Where("id IN ?", ids).Find(&tasks)
+        tasksByID := make(map[uint]models.Task, len(tasks))
+        for _, task := range tasks {
+            tasksByID[task.ID] = task
+        }
+
+        // Pertahankan urutan ranking dari query search
+        for _, hit := range hits {
+            task, ok := tasksByID[hit.ID]
+            if !ok {
+                continue
+            }
+            results = append(results, TaskSearchResult{
+                Task:                 task,
+                Rank:                 hit.Rank,
+                TitleHighlight:       renderHighlight(hit.TitleHighlight),
+                DescriptionHighlight: renderHighlight(hit.DescriptionHighlight),
+            })
+        }
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": results,
+        "count": len(results),
+        "total": total,
+        "limit": limit,
+        "offset": offset,
+    })
+}
+
+// renderHighlight meng-escape HTML dari teks task lalu mengganti penanda highlight dengan <mark>,
+// sehingga hanya tag <mark> yang aman untuk di-render client.
+func renderHighlight(text string) string {
+    escaped := html.EscapeString(text)
+    escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+    return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
+
+// buildTSQuery mengubah input user menjadi tsquery yang aman:
+// "frasa kata" -> frasa <-> kata, kata* -> kata:*, kata lain digabung dengan &
+func buildTSQuery(input string) string {
+    var terms []string
+
+    parts := strings.Split(input, "\"")
+    for i, part := range parts {
+        // Bagian dengan index ganjil berada di dalam tanda kutip
+        if i%2 == 1 {
+            var words []string
+            for _, word := range strings.Fields(part) {
+                words = append(words, splitLexemes(word)...)
+            }
+            if len(words) > 0 {
+                terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+            }
+            continue
+        }
+
+        for _, word := range strings.Fields(part) {
+            lexemes := splitLexemes(word)
+            if len(lexemes) == 0 {
+                continue
+            }
+            if strings.HasSuffix(word, "*") {
+                lexemes[len(lexemes)-1] += ":*"
+            }
+            if len(lexemes) == 1 {
+                terms = append(terms, lexemes[0])
+            } else {
+                terms = append(terms, "("+strings.Join(lexemes, " <-> ")+")")
+            }
+        }
+    }
+
+    return strings.Join(terms, " & ")
+}
+
+// splitLexemes memecah kata di karakter selain huruf / angka, sama seperti parser 'simple' memecah
+// "e-mail" atau "v1.2" menjadi beberapa lexeme. Lexeme-nya kemudian dicari berurutan (<->).
+func splitLexemes(word string) []string {
+    return strings.FieldsFunc(strings.ToLower(word), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import "testing"
+
+func TestBuildTSQuery(t *testing.T) {
+    tests := []struct {
+        input string
+        want  string
+    }{
+        {"laporan mingguan", "laporan & mingguan"},
+        {"lapor*", "lapor:*"},
+        {`"laporan mingguan" budget`, "(laporan <-> mingguan) & budget"},
+        // Tanda baca memisahkan lexeme, bukan dibuang
+        {"e-mail", "(e <-> mail)"},
+        {"v1.2", "(v1 <-> 2)"},
+        {"e-ma*", "(e <-> ma:*)"},
+        {`"kirim e-mail"`, "(kirim <-> e <-> mail)"},
+        {"' & | !", ""},
+    }
+    for _, tt := range tests {
+        if got := buildTSQuery(tt.input); got != tt.want {
+            t.Errorf("buildTSQuery(%q) = %q, want %q", tt.input, got, tt.want)
+        }
+    }
+}
@@ -1,30 +1,57 @@
 package controllers
 
 import (
+    "log"
     "net/http"
     "taskflow-api/config"
     "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
     
     "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
 )
 
 func GetUserTasks(c *gin.Context) {
-    userID := c.Param("id")
-    status := c.Query("status")
-    categoryID := c.Query("category_id")
+    userID, ok := authorizeUserParam(c, c.Param("id"), models.PermissionReadAllData)
+    if !ok {
+        return
+    }
     
-    query := config.DB.Preload("Category").Where("user_id = ?", userID)
+    listTasks(c, userID)
+}
+
+func listTasks(c *gin.Context, userID uint) {
+    opts, err := parseTaskListOptions(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid query parameter",
+            "details": err.Error(),
+        })
+        return
+    }
     
-    if status != "" {
-        query = query.Where("status = ?", status)
+    query, err := applyTaskFilters(c, config.DB.Model(&models.Task{}).Where("tasks.user_id = ?", userID))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid query parameter",
+            "details": err.Error(),
+        })
+        return
     }
+    query = query.Session(&gorm.Session{})
     
-    if categoryID != "" {
-        query = query.Where("category_id = ?", categoryID)
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch tasks",
+            "details": err.Error(),
+        })
+        return
     }
     
     var tasks []models.Task
-    result := query.Order("created_at DESC").Find(&tasks)
+    result := applyTaskOrder(query.Preload("Category"), opts).Find(&tasks)
     
     if result.Error != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
@@ -34,10 +61,30 @@ func GetUserTasks(c *gin.Context) {
         return
     }
     
+    hasMore := opts.Paginate && len(tasks) > opts.Limit
+    if hasMore {
+        tasks = tasks[:opts.Limit]
+    }
+    
+    pagination := gin.H{
+        "sort":        opts.Sort,
+        "order":       map[bool]string{true: "desc", false: "asc"}[opts.Desc],
+        "has_more":    hasMore,
+        "next_cursor": nil,
+        "total":       total,
+    }
+    if opts.Paginate {
+        pagination["limit"] = opts.Limit
+    }
+    if hasMore {
+        pagination["next_cursor"] = nextTaskCursor(tasks, opts)
+    }
+    
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data": tasks,
         "count": len(tasks),
+        "pagination": pagination,
     })
 }
 
@@ -52,6 +99,22 @@ func CreateTask(c *gin.Context) {
         return
     }
     
+    if req.UserID == 0 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": "user_id is required",
+        })
+        return
+    }
+    
+    if !authorizeUserID(c, req.UserID, models.PermissionWriteAllData) {
+        return
+    }
+    
+    createTask(c, req)
+}
+
+func createTask(c *gin.Context, req models.CreateTaskRequest) {
     // Validate user exists
     var user models.User
     if err := config.DB.First(&user, req.UserID).Error; err != nil {
@@ -61,30 +124,61 @@ func CreateTask(c *gin.Context) {
         return
     }
     
-    // Validate category exists
-    var category models.Category
-    if err := config.DB.First(&category, req.CategoryID).Error; err != nil {
+    // Validate category exists (global or one of the user's private categories)
+    if !categoryAccessible(req.CategoryID, req.UserID) {
         c.JSON(http.StatusBadRequest, gin.H{
             "error": "Category not found",
         })
         return
     }
     
+    if req.RecurrenceRule != "" && !validateRecurrence(c, req.RecurrenceRule, req.Deadline) {
+        return
+    }
+    
     task := models.Task{
-        Title:       req.Title,
-        Description: req.Description,
-        Status:      getOrDefault(req.Status, "todo"),
-        Priority:    getOrDefault(req.Priority, "medium"),
-        UserID:      req.UserID,
-        CategoryID:  req.CategoryID,
-        Deadline:    req.Deadline,
+        Title:          req.Title,
+        Description:    req.Description,
+        Status:         getOrDefault(req.Status, "todo"),
+        Priority:       getOrDefault(req.Priority, "medium"),
+        UserID:         req.UserID,
+        CategoryID:     req.CategoryID,
+        Deadline:       req.Deadline,
+        RecurrenceRule: req.RecurrenceRule,
     }
     
-    result := config.DB.Create(&task)
-    if result.Error != nil {
+    // Tanpa reminder_offsets, pakai default reminder milik user
+    offsets := req.ReminderOffsets
+    if offsets == nil {
+        offsets = services.NewReminderService().OffsetsForUser(user)
+    }
+    
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&task).Error; err != nil {
+            return err
+        }
+        
+        // Task berulang menjadi occurrence pertama dari series-nya sendiri
+        if task.RecurrenceRule != "" {
+            task.SeriesID = &task.ID
+            task.OccurrenceNumber = 1
+            if err := tx.Model(&task).UpdateColumns(map[string]interface{}{"series_id": task.ID, "occurrence_number": 1}).Error; err != nil {
+                return err
+            }
+        }
+        
+        if err := services.NewReminderService().SetTaskReminders(tx, task, offsets); err != nil {
+            return err
+        }
+        if err := services.RecordAudit(tx, auditContext(c), models.AuditTaskCreate, models.AggregateTask, task.ID, nil, task); err != nil {
+            return err
+        }
+        return services.RecordTaskEvent(tx, models.EventTaskCreated, task, actorID(c), nil)
+    })
+    if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to create task",
-            "details": result.Error.Error(),
+            "details": err.Error(),
         })
         return
     }
@@ -100,7 +194,10 @@ func CreateTask(c *gin.Context) {
 }
 
 func UpdateTask(c *gin.Context) {
-    id := c.Param("id")
+    id, ok := parseIDParam(c, "id", "task")
+    if !ok {
+        return
+    }
     
     var task models.Task
     result := config.DB.Preload("User").First(&task, id)
@@ -111,6 +208,14 @@ func UpdateTask(c *gin.Context) {
         return
     }
     
+    if !authorizeTask(c, task, models.PermissionWriteAllData) {
+        return
+    }
+    
+    updateTask(c, task)
+}
+
+func updateTask(c *gin.Context, task models.Task) {
     var req models.UpdateTaskRequest
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{
@@ -120,7 +225,10 @@ func UpdateTask(c *gin.Context) {
         return
     }
     
+    before := task
     oldStatus := task.Status
+    oldDeadline := task.Deadline
+    oldCategoryID := task.CategoryID
     
     // Update fields if provided
     if req.Title != "" {
@@ -136,9 +244,8 @@ func UpdateTask(c *gin.Context) {
         task.Priority = req.Priority
     }
     if req.CategoryID != 0 {
-        // Validate category exists
-        var category models.Category
-        if err := config.DB.First(&category, req.CategoryID).Error; err != nil {
+        // Validate category exists (global or one of the owner's private categories)
+        if !categoryAccessible(req.CategoryID, task.UserID) {
             c.JSON(http.StatusBadRequest, gin.H{
                 "error": "Category not found",
             })
@@ -146,49 +253,258 @@ func UpdateTask(c *gin.Context) {
         }
         task.CategoryID = req.CategoryID
     }
-    if req.Deadline != nil {
+    deadlineChanged := req.Deadline != nil && (oldDeadline == nil || !oldDeadline.Equal(*req.Deadline))
+    if deadlineChanged {
         task.Deadline = req.Deadline
+        task.ReminderSentAt = nil
+    }
+    if req.RecurrenceRule != nil {
+        if *req.RecurrenceRule != "" {
+            if !validateRecurrence(c, *req.RecurrenceRule, task.Deadline) {
+                return
+            }
+            if task.SeriesID == nil {
+                task.SeriesID = &task.ID
+                task.OccurrenceNumber = 1
+            }
+        }
+        task.RecurrenceRule = *req.RecurrenceRule
     }
     
-    config.DB.Save(&task)
-    config.DB.Preload("Category").Preload("User").First(&task, task.ID)
+    // Event ditulis dalam transaksi yang sama; notifikasi dan webhook dikirim oleh subscriber outbox
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&task).Error; err != nil {
+            return err
+        }
+        // Task selesai berarti semua item checklist-nya juga selesai; progress dibaca ulang untuk audit dan event
+        if oldStatus != "done" && task.Status == "done" {
+            if err := completeChecklist(tx, task.ID); err != nil {
+                return err
+            }
+            if err := tx.Model(&models.Task{}).Select("progress").Where("id = ?", task.ID).Scan(&task.Progress).Error; err != nil {
+                return err
+            }
+        }
+        if err := services.RecordAudit(tx, auditContext(c), models.AuditTaskUpdate, models.AggregateTask, task.ID, before, task); err != nil {
+            return err
+        }
+        if err := services.RecordTaskChanges(tx, before, task, actorID(c)); err != nil {
+            return err
+        }
+        if before.RecurrenceRule != "" && task.RecurrenceRule == "" && task.SeriesID != nil {
+            if err := stopRecurringSeries(tx, c, task); err != nil {
+                return err
+            }
+        }
+        var changes map[string]interface{}
+        if task.CategoryID != oldCategoryID {
+            changes = map[string]interface{}{"previous_category_id": oldCategoryID}
+        }
+        if err := services.RecordTaskEvent(tx, models.EventTaskUpdated, task, actorID(c), changes); err != nil {
+            return err
+        }
+        if oldStatus != task.Status {
+            return services.RecordTaskEvent(tx, models.EventTaskStatusChanged, task, actorID(c), map[string]interface{}{
+                "previous_status": oldStatus,
+            })
+        }
+        return nil
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update task",
+            "details": err.Error(),
+        })
+        return
+    }
+    
+    if req.ReminderOffsets != nil || deadlineChanged {
+        updateTaskReminders(task, req.ReminderOffsets, oldDeadline == nil)
+    }
+    
+    var nextOccurrence *models.Task
+    if oldStatus != "done" && task.Status == "done" {
+        // Task berulang: buat occurrence berikutnya dengan deadline baru dan reminder yang di-reset
+        next, err := services.NewRecurrenceService().GenerateNextOccurrence(task)
+        if err != nil {
+            log.Printf("⚠️  Failed to generate next occurrence for task %d: %v", task.ID, err)
+        }
+        nextOccurrence = next
+    }
+    
+    config.DB.Preload("Category").Preload("User").Preload("ChecklistItems", orderChecklist).First(&task, task.ID)
     
-    // TODO: Send notification if status changed
-    if oldStatus != task.Status && (task.Status == "in_progress" || task.Status == "done") {
-        // Log for now, implement actual notification later
+    if oldStatus != task.Status {
         c.Header("X-Status-Change", "true")
     }
     
-    c.JSON(http.StatusOK, gin.H{
+    response := gin.H{
         "success": true,
         "message": "Task updated successfully",
         "data": task,
-    })
+    }
+    if nextOccurrence != nil {
+        response["next_occurrence"] = nextOccurrence
+    }
+    
+    c.JSON(http.StatusOK, response)
 }
 
 func DeleteTask(c *gin.Context) {
-    id := c.Param("id")
+    id, ok := parseIDParam(c, "id", "task")
+    if !ok {
+        return
+    }
     
-    result := config.DB.Delete(&models.Task{}, id)
-    if result.Error != nil {
+    var task models.Task
+    if err := config.DB.First(&task, id).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Task not found",
+        })
+        return
+    }
+    
+    if !authorizeTask(c, task, models.PermissionWriteAllData) {
+        return
+    }
+    
+    deleteTask(c, task)
+}
+
+func deleteTask(c *gin.Context, task models.Task) {
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        // Item checklist dan riwayat task (task_changes, task_reminder_sends) dibiarkan: task hanya
+        // di-soft-delete, jadi isi dan timeline-nya tetap utuh bersama task
+        if err := tx.Where("task_id = ?", task.ID).Delete(&models.TaskReminder{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&task).Error; err != nil {
+            return err
+        }
+        if err := services.RecordAudit(tx, auditContext(c), models.AuditTaskDelete, models.AggregateTask, task.ID, task, nil); err != nil {
+            return err
+        }
+        return services.RecordTaskEvent(tx, models.EventTaskDeleted, task, actorID(c), nil)
+    })
+    if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to delete task",
-            "details": result.Error.Error(),
+            "details": err.Error(),
         })
         return
     }
     
-    if result.RowsAffected == 0 {
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Task deleted successfully",
+    })
+}
+
+// GetTaskHistory - timeline task: dibuat, perubahan field, perubahan status dan reminder terkirim (urut waktu)
+func GetTaskHistory(c *gin.Context) {
+    taskID, ok := parseIDParam(c, "id", "task")
+    if !ok {
+        return
+    }
+
+    var task models.Task
+    if err := config.DB.First(&task, taskID).Error; err != nil {
         c.JSON(http.StatusNotFound, gin.H{
             "error": "Task not found",
         })
         return
     }
-    
+
+    if !authorizeTask(c, task, models.PermissionReadAllData) {
+        return
+    }
+
+    history, err := services.TaskHistory(task)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch task history",
+            "details": err.Error(),
+        })
+        return
+    }
+
     c.JSON(http.StatusOK, gin.H{
         "success": true,
-        "message": "Task deleted successfully",
+        "data": history,
+        "count": len(history),
+    })
+}
+
+// updateTaskReminders memasang ulang reminder task; offsets nil berarti pertahankan offset yang ada
+// (atau default user jika task sebelumnya belum punya deadline)
+func updateTaskReminders(task models.Task, offsets []int, hadNoDeadline bool) {
+    reminderService := services.NewReminderService()
+    
+    if offsets == nil {
+        offsets = reminderService.TaskOffsets(config.DB, task.ID)
+        if len(offsets) == 0 && hadNoDeadline {
+            var owner models.User
+            config.DB.First(&owner, task.UserID)
+            offsets = reminderService.OffsetsForUser(owner)
+        }
+    }
+    
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := reminderService.SetTaskReminders(tx, task, offsets); err != nil {
+            return err
+        }
+        return reminderService.RescheduleTaskReminders(tx, task)
     })
+    if err != nil {
+        log.Printf("⚠️  Failed to update reminders for task %d: %v", task.ID, err)
+    }
+}
+
+// stopRecurringSeries menghapus aturan pengulangan dari occurrence lain di series yang sama, termasuk yang
+// sudah dibuat lebih awal oleh worker; jika tidak, worker melanjutkan series dari occurrence terakhir
+func stopRecurringSeries(tx *gorm.DB, c *gin.Context, task models.Task) error {
+    var occurrences []models.Task
+    err := tx.Where("series_id = ? AND id <> ? AND recurrence_rule <> ''", *task.SeriesID, task.ID).
+        Find(&occurrences).Error
+    if err != nil {
+        return err
+    }
+
+    for _, occurrence := range occurrences {
+        before := occurrence
+        occurrence.RecurrenceRule = ""
+        if err := tx.Model(&occurrence).Update("recurrence_rule", "").Error; err != nil {
+            return err
+        }
+        if err := services.RecordAudit(tx, auditContext(c), models.AuditTaskUpdate, models.AggregateTask, occurrence.ID, before, occurrence); err != nil {
+            return err
+        }
+        if err := services.RecordTaskChanges(tx, before, occurrence, actorID(c)); err != nil {
+            return err
+        }
+        if err := services.RecordTaskEvent(tx, models.EventTaskUpdated, occurrence, actorID(c), nil); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func validateRecurrence(c *gin.Context, rule string, deadline *time.Time) bool {
+    if _, err := services.ParseRRule(rule); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid recurrence rule",
+            "details": err.Error(),
+        })
+        return false
+    }
+    if deadline == nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid recurrence rule",
+            "details": "recurring tasks need a deadline",
+        })
+        return false
+    }
+    return true
 }
 
 func getOrDefault(value, defaultValue string) string {
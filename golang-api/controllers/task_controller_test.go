@@ -0,0 +1,179 @@
+package controllers
+
+import (
+    "database/sql/driver"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/internal/fakedb"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// taskOwnerID - pemilik task di fake database; request dilakukan oleh user lain
+const taskOwnerID = 2
+
+// useFakeDB mengganti config.DB dengan database palsu yang hanya mengenal task 10 milik user 2
+func useFakeDB(t *testing.T) *fakedb.DB {
+    t.Helper()
+
+    db, fake := fakedb.Open(t, func(query string, args []driver.Value) fakedb.Result {
+        now := time.Now()
+        switch {
+        case strings.Contains(query, `FROM "tasks"`) && len(args) > 0 && fmt.Sprint(args[0]) == "10":
+            return fakedb.Result{
+                Columns: []string{"id", "title", "status", "priority", "user_id", "category_id", "created_at", "updated_at"},
+                Rows:    [][]driver.Value{{int64(10), "Laporan mingguan", "todo", "medium", int64(taskOwnerID), int64(1), now, now}},
+            }
+        case strings.Contains(query, `FROM "users"`):
+            return fakedb.Result{
+                Columns: []string{"id", "name", "email", "role"},
+                Rows:    [][]driver.Value{{int64(taskOwnerID), "Owner", "owner@example.com", models.RoleMember}},
+            }
+        }
+        return fakedb.Result{}
+    })
+
+    previous := config.DB
+    config.DB = db
+    t.Cleanup(func() { config.DB = previous })
+    return fake
+}
+
+// serve menjalankan handler dengan user yang sudah login, tanpa melewati AuthRequired
+func serve(user models.User, method, route, target, body string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+    gin.SetMode(gin.TestMode)
+    router := gin.New()
+    router.Handle(method, route, func(c *gin.Context) {
+        c.Set(middleware.ContextUserKey, user)
+        c.Next()
+    }, handler)
+
+    recorder := httptest.NewRecorder()
+    request := httptest.NewRequest(method, target, strings.NewReader(body))
+    request.Header.Set("Content-Type", "application/json")
+    router.ServeHTTP(recorder, request)
+    return recorder
+}
+
+func TestGetUserTasksRejectsOtherUser(t *testing.T) {
+    fake := useFakeDB(t)
+    member := models.User{ID: 1, Role: models.RoleMember}
+
+    recorder := serve(member, http.MethodGet, "/users/:id/tasks", "/users/2/tasks", "", GetUserTasks)
+    if recorder.Code != http.StatusForbidden {
+        t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusForbidden, recorder.Body)
+    }
+    if queries := fake.Queries(); len(queries) != 0 {
+        t.Errorf("queries = %v, want none before authorization", queries)
+    }
+}
+
+func TestGetUserTasksRejectsInvalidUserID(t *testing.T) {
+    useFakeDB(t)
+    member := models.User{ID: 1, Role: models.RoleMember}
+
+    recorder := serve(member, http.MethodGet, "/users/:id/tasks", "/users/abc/tasks", "", GetUserTasks)
+    if recorder.Code != http.StatusBadRequest {
+        t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body)
+    }
+}
+
+func TestUpdateTaskRejectsNonOwner(t *testing.T) {
+    tests := []struct {
+        name string
+        user models.User
+    }{
+        {"member", models.User{ID: 1, Role: models.RoleMember}},
+        // Auditor boleh membaca semua data, tapi tidak boleh mengubahnya
+        {"auditor", models.User{ID: 3, Role: models.RoleAuditor}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fake := useFakeDB(t)
+
+            recorder := serve(tt.user, http.MethodPut, "/tasks/:id", "/tasks/10", `{"title":"Diambil alih"}`, UpdateTask)
+            if recorder.Code != http.StatusForbidden {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusForbidden, recorder.Body)
+            }
+            if writes := fake.Writes(); len(writes) != 0 {
+                t.Errorf("writes = %v, want none", writes)
+            }
+        })
+    }
+}
+
+func TestUpdateTaskNotFound(t *testing.T) {
+    useFakeDB(t)
+    member := models.User{ID: 1, Role: models.RoleMember}
+
+    recorder := serve(member, http.MethodPut, "/tasks/:id", "/tasks/99", `{"title":"x"}`, UpdateTask)
+    if recorder.Code != http.StatusNotFound {
+        t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusNotFound, recorder.Body)
+    }
+}
+
+// ID non-numerik tidak boleh sampai ke First, karena GORM menganggapnya potongan SQL
+func TestTaskHandlersRejectNonNumericID(t *testing.T) {
+    handlers := map[string]gin.HandlerFunc{
+        http.MethodPut:    UpdateTask,
+        http.MethodDelete: DeleteTask,
+    }
+    for method, handler := range handlers {
+        t.Run(method, func(t *testing.T) {
+            fake := useFakeDB(t)
+            member := models.User{ID: 1, Role: models.RoleMember}
+
+            recorder := serve(member, method, "/tasks/:id", "/tasks/id%20%3E%200", `{"title":"x"}`, handler)
+            if recorder.Code != http.StatusBadRequest {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body)
+            }
+            if queries := fake.Queries(); len(queries) != 0 {
+                t.Errorf("queries = %v, want none", queries)
+            }
+        })
+    }
+}
+
+func TestDeleteTaskRejectsNonOwner(t *testing.T) {
+    tests := []struct {
+        name string
+        user models.User
+    }{
+        {"member", models.User{ID: 1, Role: models.RoleMember}},
+        {"auditor", models.User{ID: 3, Role: models.RoleAuditor}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fake := useFakeDB(t)
+
+            recorder := serve(tt.user, http.MethodDelete, "/tasks/:id", "/tasks/10", "", DeleteTask)
+            if recorder.Code != http.StatusForbidden {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusForbidden, recorder.Body)
+            }
+            if writes := fake.Writes(); len(writes) != 0 {
+                t.Errorf("writes = %v, want none", writes)
+            }
+        })
+    }
+}
+
+func TestDeleteTaskAllowsOwnerToReachDelete(t *testing.T) {
+    fake := useFakeDB(t)
+    owner := models.User{ID: taskOwnerID, Role: models.RoleMember}
+
+    // Fake database menolak penulisan, jadi yang diuji hanya bahwa pemilik lolos pengecekan akses
+    recorder := serve(owner, http.MethodDelete, "/tasks/:id", "/tasks/10", "", DeleteTask)
+    if recorder.Code == http.StatusForbidden {
+        t.Fatalf("owner was rejected: %s", recorder.Body)
+    }
+    if len(fake.Writes()) == 0 {
+        t.Error("owner delete did not attempt any write")
+    }
+}
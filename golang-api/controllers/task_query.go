@@ -0,0 +1,279 @@
+package controllers
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
+)
+
+const (
+    defaultTaskPageSize = 50
+    maxTaskPageSize     = 200
+)
+
+// taskSort - ekspresi SQL untuk sorting beserta cara mengambil nilainya dari task (untuk cursor)
+type taskSort struct {
+    expr        string
+    defaultDesc bool
+    value       func(task models.Task) interface{}
+}
+
+var taskSorts = map[string]taskSort{
+    "created_at": {
+        expr:        "tasks.created_at",
+        defaultDesc: true,
+        value:       func(t models.Task) interface{} { return t.CreatedAt.Format(time.RFC3339Nano) },
+    },
+    "updated_at": {
+        expr:        "tasks.updated_at",
+        defaultDesc: true,
+        value:       func(t models.Task) interface{} { return t.UpdatedAt.Format(time.RFC3339Nano) },
+    },
+    "deadline": {
+        // Task tanpa deadline selalu di akhir saat ascending
+        expr:        "COALESCE(tasks.deadline, 'infinity'::timestamptz)",
+        defaultDesc: false,
+        value: func(t models.Task) interface{} {
+            if t.Deadline == nil {
+                return "infinity"
+            }
+            return t.Deadline.Format(time.RFC3339Nano)
+        },
+    },
+    "priority": {
+        expr:        "CASE tasks.priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END",
+        defaultDesc: true,
+        value:       func(t models.Task) interface{} { return priorityRank(t.Priority) },
+    },
+    "title": {
+        expr:        "LOWER(tasks.title)",
+        defaultDesc: false,
+        value:       func(t models.Task) interface{} { return strings.ToLower(t.Title) },
+    },
+}
+
+// taskCursor - posisi terakhir pada halaman sebelumnya, di-encode sebagai base64 JSON (opaque untuk client)
+type taskCursor struct {
+    Sort  string      `json:"s"`
+    Desc  bool        `json:"d"`
+    Value interface{} `json:"v"`
+    ID    uint        `json:"id"`
+}
+
+type taskListOptions struct {
+    Sort     string
+    Desc     bool
+    Limit    int
+    Paginate bool
+    Cursor   *taskCursor
+}
+
+// applyTaskFilters menerapkan filter dari query string ke query task
+func applyTaskFilters(c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+    if status := c.Query("status"); status != "" {
+        query = query.Where("tasks.status IN ?", strings.Split(status, ","))
+    }
+
+    if categoryID := c.Query("category_id"); categoryID != "" {
+        query = query.Where("tasks.category_id = ?", categoryID)
+    }
+
+    if priority := c.Query("priority"); priority != "" {
+        query = query.Where("tasks.priority IN ?", strings.Split(priority, ","))
+    }
+
+    // Tanggal tanpa jam dibaca di timezone user; batas _to mencakup seluruh hari tersebut
+    user, _ := middleware.CurrentUser(c)
+    loc := user.Location()
+    timeRanges := []struct {
+        param  string
+        column string
+        upper  bool
+    }{
+        {"deadline_from", "tasks.deadline", false},
+        {"deadline_to", "tasks.deadline", true},
+        {"created_from", "tasks.created_at", false},
+        {"created_to", "tasks.created_at", true},
+        {"updated_from", "tasks.updated_at", false},
+        {"updated_to", "tasks.updated_at", true},
+    }
+    for _, r := range timeRanges {
+        value := c.Query(r.param)
+        if value == "" {
+            continue
+        }
+        t, dateOnly, err := parseQueryTime(value, loc)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %v", r.param, err)
+        }
+        switch {
+        case !r.upper:
+            query = query.Where(r.column+" >= ?", t)
+        case dateOnly:
+            query = query.Where(r.column+" < ?", t.AddDate(0, 0, 1))
+        default:
+            query = query.Where(r.column+" <= ?", t)
+        }
+    }
+
+    if hasDeadline := c.Query("has_deadline"); hasDeadline != "" {
+        b, err := strconv.ParseBool(hasDeadline)
+        if err != nil {
+            return nil, fmt.Errorf("has_deadline must be true or false")
+        }
+        if b {
+            query = query.Where("tasks.deadline IS NOT NULL")
+        } else {
+            query = query.Where("tasks.deadline IS NULL")
+        }
+    }
+
+    if overdue := c.Query("overdue"); overdue != "" {
+        b, err := strconv.ParseBool(overdue)
+        if err != nil {
+            return nil, fmt.Errorf("overdue must be true or false")
+        }
+        if b {
+            query = query.Where("tasks.deadline < ? AND tasks.status <> ?", time.Now(), "done")
+        } else {
+            query = query.Where("tasks.deadline IS NULL OR tasks.deadline >= ? OR tasks.status = ?", time.Now(), "done")
+        }
+    }
+
+    return query, nil
+}
+
+// parseTaskListOptions membaca sort, order, limit dan cursor.
+// Tanpa limit maupun cursor, semua task dikembalikan seperti sebelumnya.
+func parseTaskListOptions(c *gin.Context) (taskListOptions, error) {
+    opts := taskListOptions{Sort: c.DefaultQuery("sort", "created_at")}
+
+    sort, ok := taskSorts[opts.Sort]
+    if !ok {
+        return opts, fmt.Errorf("sort must be one of created_at, updated_at, deadline, priority, title")
+    }
+
+    switch c.Query("order") {
+    case "":
+        opts.Desc = sort.defaultDesc
+    case "asc":
+        opts.Desc = false
+    case "desc":
+        opts.Desc = true
+    default:
+        return opts, fmt.Errorf("order must be asc or desc")
+    }
+
+    limitParam := c.Query("limit")
+    cursorParam := c.Query("cursor")
+    opts.Paginate = limitParam != "" || cursorParam != ""
+
+    opts.Limit = defaultTaskPageSize
+    if limitParam != "" {
+        limit, err := strconv.Atoi(limitParam)
+        if err != nil || limit < 1 {
+            return opts, fmt.Errorf("limit must be a positive number")
+        }
+        if limit > maxTaskPageSize {
+            limit = maxTaskPageSize
+        }
+        opts.Limit = limit
+    }
+
+    if cursorParam != "" {
+        cursor, err := decodeTaskCursor(cursorParam)
+        if err != nil {
+            return opts, fmt.Errorf("cursor is invalid")
+        }
+        if cursor.Sort != opts.Sort || cursor.Desc != opts.Desc {
+            return opts, fmt.Errorf("cursor does not match the requested sort order")
+        }
+        opts.Cursor = &cursor
+    }
+
+    return opts, nil
+}
+
+// applyTaskOrder menerapkan ORDER BY dan posisi cursor (keyset pagination dengan id sebagai tie-breaker)
+func applyTaskOrder(query *gorm.DB, opts taskListOptions) *gorm.DB {
+    sort := taskSorts[opts.Sort]
+    direction, comparator := "ASC", ">"
+    if opts.Desc {
+        direction, comparator = "DESC", "<"
+    }
+
+    if opts.Cursor != nil {
+        query = query.Where(fmt.Sprintf("(%s, tasks.id) %s (?, ?)", sort.expr, comparator), opts.Cursor.Value, opts.Cursor.ID)
+    }
+
+    query = query.Order(fmt.Sprintf("%s %s, tasks.id %s", sort.expr, direction, direction))
+    if opts.Paginate {
+        // Ambil satu baris ekstra untuk mengetahui apakah masih ada halaman berikutnya
+        query = query.Limit(opts.Limit + 1)
+    }
+    return query
+}
+
+func nextTaskCursor(tasks []models.Task, opts taskListOptions) string {
+    if len(tasks) == 0 {
+        return ""
+    }
+    last := tasks[len(tasks)-1]
+    return encodeTaskCursor(taskCursor{
+        Sort:  opts.Sort,
+        Desc:  opts.Desc,
+        Value: taskSorts[opts.Sort].value(last),
+        ID:    last.ID,
+    })
+}
+
+func encodeTaskCursor(cursor taskCursor) string {
+    data, _ := json.Marshal(cursor)
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTaskCursor(value string) (taskCursor, error) {
+    var cursor taskCursor
+    data, err := base64.RawURLEncoding.DecodeString(value)
+    if err != nil {
+        return cursor, err
+    }
+    if err := json.Unmarshal(data, &cursor); err != nil {
+        return cursor, err
+    }
+    if _, ok := taskSorts[cursor.Sort]; !ok || cursor.ID == 0 {
+        return cursor, fmt.Errorf("unknown cursor")
+    }
+    return cursor, nil
+}
+
+// parseQueryTime menerima RFC3339 atau YYYY-MM-DD (awal hari di loc); dateOnly menandai format kedua
+func parseQueryTime(value string, loc *time.Location) (time.Time, bool, error) {
+    if t, err := time.Parse(time.RFC3339, value); err == nil {
+        return t, false, nil
+    }
+    t, err := time.ParseInLocation("2006-01-02", value, loc)
+    if err != nil {
+        return t, false, fmt.Errorf("must be RFC3339 or YYYY-MM-DD")
+    }
+    return t, true, nil
+}
+
+func priorityRank(priority string) int {
+    switch priority {
+    case "high":
+        return 3
+    case "medium":
+        return 2
+    default:
+        return 1
+    }
+}
@@ -1,11 +1,17 @@
 package controllers
 
 import (
+    "errors"
+    "log"
     "net/http"
+    "strings"
     "taskflow-api/config"
+    "taskflow-api/middleware"
     "taskflow-api/models"
+    "taskflow-api/services"
     
     "github.com/gin-gonic/gin"
+    "gorm.io/gorm"
 )
 
 func CreateUser(c *gin.Context) {
@@ -19,27 +25,69 @@ func CreateUser(c *gin.Context) {
         return
     }
     
+    // Firebase UID selalu diambil dari token yang sudah diverifikasi (route ini di belakang VerifyToken),
+    // tidak pernah dari body request
+    firebaseUID := middleware.CurrentFirebaseUID(c)
+    
+    // Email dinormalkan seperti di Register, supaya huruf besar/kecil tidak membuat akun ganda
+    email := strings.ToLower(strings.TrimSpace(req.Email))
+    var count int64
+    config.DB.Model(&models.User{}).Where("LOWER(email) = ?", email).Count(&count)
+    if count > 0 {
+        c.JSON(http.StatusConflict, gin.H{
+            "error": "Email is already registered",
+        })
+        return
+    }
+    
     user := models.User{
         Name:        req.Name,
-        Email:       req.Email,
-        FirebaseUID: req.FirebaseUID,
-        FCMToken:    req.FCMToken,
+        Email:       email,
+        FirebaseUID: firebaseUID,
     }
     
     // Set password if provided
     if req.Password != "" {
-        user.Password = req.Password
+        hash, err := services.NewAuthService().HashPassword(req.Password)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": "Failed to hash password",
+                "details": err.Error(),
+            })
+            return
+        }
+        user.Password = hash
     }
     
-    result := config.DB.Create(&user)
-    if result.Error != nil {
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&user).Error; err != nil {
+            return err
+        }
+        if err := recordUserCreatedAudit(tx, c, user); err != nil {
+            return err
+        }
+        return recordUserEvent(tx, c, models.EventUserCreated, user, nil)
+    })
+    if errors.Is(err, gorm.ErrDuplicatedKey) {
+        c.JSON(http.StatusConflict, gin.H{
+            "error": "User already exists",
+        })
+        return
+    }
+    if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to create user",
-            "details": result.Error.Error(),
+            "details": err.Error(),
         })
         return
     }
     
+    if req.FCMToken != "" {
+        if _, err := services.NewDeviceTokenService().RegisterDevice(user.ID, req.FCMToken, "web", c.Request.UserAgent()); err != nil {
+            log.Printf("⚠️  Failed to register device for user %d: %v", user.ID, err)
+        }
+    }
+    
     c.JSON(http.StatusCreated, gin.H{
         "success": true,
         "message": "User created successfully",
@@ -49,6 +97,12 @@ func CreateUser(c *gin.Context) {
 
 func GetUserByFirebaseUID(c *gin.Context) {
     firebaseUID := c.Param("firebase_uid")
+    if firebaseUID != middleware.CurrentFirebaseUID(c) && !middleware.HasPermission(c, models.PermissionReadAllData) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error": "You do not have access to this resource",
+        })
+        return
+    }
     
     var user models.User
     result := config.DB.Where("firebase_uid = ?", firebaseUID).First(&user)
@@ -66,10 +120,40 @@ func GetUserByFirebaseUID(c *gin.Context) {
     })
 }
 
-func UpdateFCMToken(c *gin.Context) {
-    userID := c.Param("id")
+func GetUserById(c *gin.Context) {
+    userID, ok := authorizeUserParam(c, c.Param("id"), models.PermissionReadAllData)
+    if !ok {
+        return
+    }
+    
+    var user models.User
+    result := config.DB.Preload("Tasks").Preload("Categories").First(&user, userID)
+    
+    if result.Error != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "User not found",
+        })
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": user,
+    })
+}
+
+func UpdateProfile(c *gin.Context) {
+    userID, ok := authorizeUserParam(c, c.Param("id"), models.PermissionWriteAllData)
+    if !ok {
+        return
+    }
+    
+    var req struct {
+        Name            string `json:"name"`
+        Email           string `json:"email" binding:"omitempty,email"`
+        CurrentPassword string `json:"current_password"` // wajib jika email diganti
+    }
     
-    var req models.UpdateFCMTokenRequest
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{
             "error": "Invalid request format",
@@ -87,43 +171,90 @@ func UpdateFCMToken(c *gin.Context) {
         return
     }
     
-    user.FCMToken = req.FCMToken
-    config.DB.Save(&user)
+    before := user
+    if req.Name != "" {
+        user.Name = req.Name
+    }
+    if email := strings.ToLower(strings.TrimSpace(req.Email)); email != "" && email != user.Email {
+        // Email dipakai untuk login native, jadi hanya boleh diganti dari sesi interaktif
+        // dengan password saat ini (atau login ulang) milik user yang melakukan perubahan
+        if _, isAPIKey := middleware.CurrentAPIKey(c); isAPIKey {
+            c.JSON(http.StatusForbidden, gin.H{
+                "error": "Email cannot be changed with an API key",
+            })
+            return
+        }
+        actor, _ := middleware.CurrentUser(c)
+        if actor.ID == user.ID {
+            actor = user
+        }
+        if !confirmCredentials(c, actor, req.CurrentPassword) {
+            return
+        }
+        user.Email = email
+    }
+    
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&user).Error; err != nil {
+            return err
+        }
+        if err := services.RecordAudit(tx, auditContext(c), models.AuditUserUpdate, models.AggregateUser, user.ID, before, user); err != nil {
+            return err
+        }
+        return recordUserEvent(tx, c, models.EventUserUpdated, user, nil)
+    })
+    if errors.Is(err, gorm.ErrDuplicatedKey) {
+        c.JSON(http.StatusConflict, gin.H{
+            "error": "Email is already registered",
+        })
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update profile",
+            "details": err.Error(),
+        })
+        return
+    }
     
     c.JSON(http.StatusOK, gin.H{
         "success": true,
-        "message": "FCM token updated successfully",
+        "message": "Profile updated successfully",
         "data": user,
     })
 }
 
-func GetUserById(c *gin.Context) {
-    userID := c.Param("id")
+func GetUsers(c *gin.Context) {
+    query := config.DB.Order("created_at DESC")
     
-    var user models.User
-    result := config.DB.Preload("Tasks").Preload("Categories").First(&user, userID)
+    if role := c.Query("role"); role != "" {
+        query = query.Where("role = ?", role)
+    }
     
+    var users []models.User
+    result := query.Find(&users)
     if result.Error != nil {
-        c.JSON(http.StatusNotFound, gin.H{
-            "error": "User not found",
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch users",
+            "details": result.Error.Error(),
         })
         return
     }
     
     c.JSON(http.StatusOK, gin.H{
         "success": true,
-        "data": user,
+        "data": users,
+        "count": len(users),
     })
 }
 
-func UpdateProfile(c *gin.Context) {
-    userID := c.Param("id")
-    
-    var req struct {
-        Name  string `json:"name"`
-        Email string `json:"email"`
+func UpdateUserRole(c *gin.Context) {
+    userID, ok := parseIDParam(c, "id", "user")
+    if !ok {
+        return
     }
     
+    var req models.UpdateRoleRequest
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{
             "error": "Invalid request format",
@@ -141,18 +272,73 @@ func UpdateProfile(c *gin.Context) {
         return
     }
     
-    if req.Name != "" {
-        user.Name = req.Name
-    }
-    if req.Email != "" {
-        user.Email = req.Email
+    // Admin tidak boleh menurunkan role dirinya sendiri, supaya selalu ada minimal satu admin
+    current, _ := middleware.CurrentUser(c)
+    if current.ID == user.ID && req.Role != models.RoleAdmin {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "You cannot remove your own admin role",
+        })
+        return
     }
     
-    config.DB.Save(&user)
+    before := user
+    previousRole := user.Role
+    user.Role = req.Role
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&user).Error; err != nil {
+            return err
+        }
+        if err := services.RecordAudit(tx, auditContext(c), models.AuditUserRoleChange, models.AggregateUser, user.ID, before, user); err != nil {
+            return err
+        }
+        return recordUserEvent(tx, c, models.EventUserUpdated, user, map[string]interface{}{
+            "previous_role": previousRole,
+        })
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update user role",
+            "details": err.Error(),
+        })
+        return
+    }
     
     c.JSON(http.StatusOK, gin.H{
         "success": true,
-        "message": "Profile updated successfully",
+        "message": "User role updated successfully",
         "data": user,
     })
-}
\ No newline at end of file
+}
+
+// recordUserEvent menulis event user ke outbox (tanpa password dan pengaturan notifikasi)
+func recordUserEvent(tx *gorm.DB, c *gin.Context, eventType string, user models.User, extra map[string]interface{}) error {
+    payload := map[string]interface{}{
+        "user": gin.H{
+            "id":           user.ID,
+            "name":         user.Name,
+            "email":        user.Email,
+            "firebase_uid": user.FirebaseUID,
+            "role":         user.Role,
+        },
+    }
+    for key, value := range extra {
+        payload[key] = value
+    }
+    return services.RecordEvent(tx, models.OutboxEvent{
+        AggregateType: models.AggregateUser,
+        AggregateID:   user.ID,
+        EventType:     eventType,
+        UserID:        user.ID,
+        ActorID:       actorID(c),
+    }, payload)
+}
+
+// recordUserCreatedAudit - registrasi belum punya user yang login, jadi actor-nya adalah user baru itu sendiri
+func recordUserCreatedAudit(tx *gorm.DB, c *gin.Context, user models.User) error {
+    actx := auditContext(c)
+    if actx.ActorID == 0 {
+        actx.ActorID = user.ID
+        actx.ActorEmail = user.Email
+    }
+    return services.RecordAudit(tx, actx, models.AuditUserCreate, models.AggregateUser, user.ID, nil, user)
+}
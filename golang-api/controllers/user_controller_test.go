@@ -0,0 +1,44 @@
+package controllers
+
+import (
+    "net/http"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+)
+
+func TestUpdateProfileEmailChangeRequiresCredentials(t *testing.T) {
+    owner := models.User{ID: taskOwnerID, Role: models.RoleMember, Email: "owner@example.com"}
+
+    tests := []struct {
+        name       string
+        apiKey     bool
+        wantStatus int
+    }{
+        // User tanpa password dan tanpa login baru-baru ini
+        {"session without recent login", false, http.StatusUnauthorized},
+        {"api key", true, http.StatusForbidden},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fake := useFakeDB(t)
+            handler := UpdateProfile
+            if tt.apiKey {
+                handler = func(c *gin.Context) {
+                    c.Set(middleware.ContextAPIKeyKey, models.APIKey{Scopes: []string{models.ScopeTasksWrite}})
+                    UpdateProfile(c)
+                }
+            }
+
+            recorder := serve(owner, http.MethodPut, "/users/:id", "/users/2", `{"email":"New@Example.com"}`, handler)
+            if recorder.Code != tt.wantStatus {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+            }
+            if writes := fake.Writes(); len(writes) != 0 {
+                t.Errorf("writes = %v, want none", writes)
+            }
+        })
+    }
+}
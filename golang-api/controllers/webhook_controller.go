@@ -0,0 +1,268 @@
+package controllers
+
+import (
+    "net/http"
+    "strconv"
+    "taskflow-api/config"
+    "taskflow-api/middleware"
+    "taskflow-api/models"
+    "taskflow-api/services"
+
+    "github.com/gin-gonic/gin"
+)
+
+const (
+    defaultDeliveryLimit = 50
+    maxDeliveryLimit     = 200
+)
+
+func GetMyWebhooks(c *gin.Context) {
+    user, _ := middleware.CurrentUser(c)
+
+    var subscriptions []models.WebhookSubscription
+    if err := config.DB.Where("user_id = ?", user.ID).Order("id ASC").Find(&subscriptions).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch webhooks",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": subscriptions,
+        "count": len(subscriptions),
+        "available_events": models.WebhookEvents,
+    })
+}
+
+func CreateMyWebhook(c *gin.Context) {
+    var req models.CreateWebhookRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+    if !validateOutboundURL(c, "url", req.URL) {
+        return
+    }
+
+    secret := req.Secret
+    if secret == "" {
+        generated, err := services.GenerateWebhookSecret()
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": "Failed to create webhook",
+                "details": err.Error(),
+            })
+            return
+        }
+        secret = generated
+    }
+
+    user, _ := middleware.CurrentUser(c)
+    subscription := models.WebhookSubscription{
+        UserID:      user.ID,
+        URL:         req.URL,
+        Secret:      secret,
+        Events:      uniqueStrings(req.Events),
+        Description: req.Description,
+        Active:      true,
+    }
+    if err := config.DB.Create(&subscription).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to create webhook",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "message": "Webhook created successfully, store the secret now as it will not be shown again",
+        "data": gin.H{
+            "webhook": subscription,
+            "secret":  secret,
+        },
+    })
+}
+
+func GetMyWebhook(c *gin.Context) {
+    subscription, ok := findMyWebhook(c)
+    if !ok {
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": subscription,
+    })
+}
+
+func UpdateMyWebhook(c *gin.Context) {
+    subscription, ok := findMyWebhook(c)
+    if !ok {
+        return
+    }
+
+    var req models.UpdateWebhookRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid request format",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if req.URL != nil {
+        if !validateOutboundURL(c, "url", *req.URL) {
+            return
+        }
+        subscription.URL = *req.URL
+    }
+    if req.Events != nil {
+        subscription.Events = uniqueStrings(*req.Events)
+    }
+    if req.Description != nil {
+        subscription.Description = *req.Description
+    }
+    if req.Active != nil {
+        subscription.Active = *req.Active
+    }
+
+    err := config.DB.Model(&subscription).Select("url", "events", "description", "active").Updates(subscription).Error
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to update webhook",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Webhook updated successfully",
+        "data": subscription,
+    })
+}
+
+// DeleteMyWebhook menghapus subscription; delivery yang masih pending otomatis gagal saat dicoba
+func DeleteMyWebhook(c *gin.Context) {
+    subscription, ok := findMyWebhook(c)
+    if !ok {
+        return
+    }
+
+    if err := config.DB.Delete(&subscription).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to delete webhook",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Webhook deleted successfully",
+    })
+}
+
+// GetMyWebhookDeliveries - log pengiriman, terbaru dulu. Filter opsional ?status=pending|succeeded|failed
+func GetMyWebhookDeliveries(c *gin.Context) {
+    subscription, ok := findMyWebhook(c)
+    if !ok {
+        return
+    }
+
+    limit := defaultDeliveryLimit
+    if value := c.Query("limit"); value != "" {
+        n, err := strconv.Atoi(value)
+        if err != nil || n < 1 || n > maxDeliveryLimit {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "Invalid query parameter",
+                "details": "limit must be between 1 and 200",
+            })
+            return
+        }
+        limit = n
+    }
+
+    query := config.DB.Where("subscription_id = ?", subscription.ID)
+    if status := c.Query("status"); status != "" {
+        query = query.Where("status = ?", status)
+    }
+
+    var deliveries []models.WebhookDelivery
+    if err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to fetch webhook deliveries",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": deliveries,
+        "count": len(deliveries),
+    })
+}
+
+// RedeliverMyWebhook mengantrikan ulang delivery dengan payload yang sama; dikirim oleh worker berikutnya
+func RedeliverMyWebhook(c *gin.Context) {
+    subscription, ok := findMyWebhook(c)
+    if !ok {
+        return
+    }
+
+    var original models.WebhookDelivery
+    err := config.DB.Where("subscription_id = ? AND id = ?", subscription.ID, c.Param("delivery_id")).First(&original).Error
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Webhook delivery not found",
+        })
+        return
+    }
+
+    delivery, err := services.NewWebhookService().Redeliver(original)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": "Failed to redeliver webhook",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusAccepted, gin.H{
+        "success": true,
+        "message": "Webhook delivery queued",
+        "data": delivery,
+    })
+}
+
+func findMyWebhook(c *gin.Context) (models.WebhookSubscription, bool) {
+    user, _ := middleware.CurrentUser(c)
+
+    var subscription models.WebhookSubscription
+    if err := config.DB.Where("user_id = ? AND id = ?", user.ID, c.Param("id")).First(&subscription).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error": "Webhook not found",
+        })
+        return subscription, false
+    }
+    return subscription, true
+}
+
+// validateOutboundURL menolak URL yang bukan http/https atau host-nya mengarah ke alamat internal
+func validateOutboundURL(c *gin.Context, field string, value string) bool {
+    if err := services.ValidateOutboundURL(c.Request.Context(), value); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "Invalid webhook URL",
+            "details": field + " " + err.Error(),
+        })
+        return false
+    }
+    return true
+}
@@ -0,0 +1,186 @@
+// Package fakedb menyediakan database palsu untuk test: query SELECT dijawab oleh fungsi dari test,
+// semua penulisan dicatat lalu ditolak (kecuali AcceptWrites). Dipakai lewat dialector postgres, jadi SQL yang dihasilkan GORM sama
+// dengan produksi.
+package fakedb
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "io"
+    "strings"
+    "sync"
+    "testing"
+
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+// ErrWrite dikembalikan untuk setiap INSERT / UPDATE / DELETE
+var ErrWrite = errors.New("fakedb: writes are not supported")
+
+// Result - baris hasil query; Rows[i][j] adalah nilai kolom Columns[j]
+type Result struct {
+    Columns []string
+    Rows    [][]driver.Value
+}
+
+// Statement - satu penulisan beserta argumennya
+type Statement struct {
+    Query string
+    Args  []driver.Value
+}
+
+// QueryFunc menjawab satu query SELECT; Result kosong berarti tidak ada baris
+type QueryFunc func(query string, args []driver.Value) Result
+
+// DB mencatat query yang dijalankan terhadap database palsu
+type DB struct {
+    query QueryFunc
+
+    mu           sync.Mutex
+    queries      []string
+    writes       []Statement
+    acceptWrites bool
+}
+
+// Open membuat *gorm.DB di atas database palsu
+func Open(t testing.TB, query QueryFunc) (*gorm.DB, *DB) {
+    t.Helper()
+
+    fake := &DB{query: query}
+    sqlDB := sql.OpenDB(connector{db: fake})
+    t.Cleanup(func() { sqlDB.Close() })
+
+    db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+        Logger:         logger.Discard,
+        TranslateError: true,
+    })
+    if err != nil {
+        t.Fatalf("fakedb: %v", err)
+    }
+    return db, fake
+}
+
+// AcceptWrites membuat penulisan berikutnya berhasil tanpa baris hasil, alih-alih ditolak dengan ErrWrite
+func (db *DB) AcceptWrites() {
+    db.mu.Lock()
+    defer db.mu.Unlock()
+    db.acceptWrites = true
+}
+
+// Queries mengembalikan semua query SELECT yang dijalankan
+func (db *DB) Queries() []string {
+    db.mu.Lock()
+    defer db.mu.Unlock()
+    return append([]string(nil), db.queries...)
+}
+
+// Writes mengembalikan semua penulisan yang dicoba
+func (db *DB) Writes() []string {
+    db.mu.Lock()
+    defer db.mu.Unlock()
+    queries := make([]string, len(db.writes))
+    for i, write := range db.writes {
+        queries[i] = write.Query
+    }
+    return queries
+}
+
+// WriteStatements - sama dengan Writes, beserta argumen setiap penulisan
+func (db *DB) WriteStatements() []Statement {
+    db.mu.Lock()
+    defer db.mu.Unlock()
+    return append([]Statement(nil), db.writes...)
+}
+
+func (db *DB) run(query string, args []driver.Value) (driver.Rows, error) {
+    db.mu.Lock()
+    defer db.mu.Unlock()
+
+    if isWrite(query) {
+        db.writes = append(db.writes, Statement{Query: query, Args: args})
+        if db.acceptWrites {
+            return &rows{}, nil
+        }
+        return nil, ErrWrite
+    }
+    db.queries = append(db.queries, query)
+
+    var result Result
+    if db.query != nil {
+        result = db.query(query, args)
+    }
+    return &rows{result: result}, nil
+}
+
+func isWrite(query string) bool {
+    verb, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+    switch strings.ToUpper(verb) {
+    case "INSERT", "UPDATE", "DELETE":
+        return true
+    }
+    return false
+}
+
+type connector struct {
+    db *DB
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return &conn{db: c.db}, nil }
+func (c connector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return nil, errors.New("fakedb: use Open") }
+
+type conn struct {
+    db *DB
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) { return &stmt{db: c.db, query: query}, nil }
+func (c *conn) Close() error                              { return nil }
+func (c *conn) Begin() (driver.Tx, error)                 { return tx{}, nil }
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type stmt struct {
+    db    *DB
+    query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+    if _, err := s.db.run(s.query, args); err != nil {
+        return nil, err
+    }
+    return driver.RowsAffected(0), nil
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+    return s.db.run(s.query, args)
+}
+
+type rows struct {
+    result Result
+    next   int
+}
+
+func (r *rows) Columns() []string { return r.result.Columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+    if r.next >= len(r.result.Rows) {
+        return io.EOF
+    }
+    copy(dest, r.result.Rows[r.next])
+    r.next++
+    return nil
+}
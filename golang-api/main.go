@@ -1,16 +1,22 @@
 package main
 
 import (
+    "context"
     "log"
+    "net/http"
     "os"
     "os/signal"
+    "strings"
     "syscall"
     "taskflow-api/config"
     "taskflow-api/models"
     "taskflow-api/routes"
+    "taskflow-api/services"
     "taskflow-api/workers"
+    "time"
     
     "github.com/joho/godotenv"
+    "gorm.io/gorm"
 )
 
 func main() {
@@ -23,20 +29,49 @@ func main() {
     
     config.ConnectDatabase()
     config.InitFirebase()
+    config.InitSMTP()
+
+    // Template notifikasi yang rusak / tidak lengkap harus ketahuan sebelum server jalan
+    if err := services.ValidateTemplates(); err != nil {
+        log.Fatal("❌ ", err)
+    }
     
     log.Println("🗄️  Running database migrations...")
     err = config.DB.AutoMigrate(
         &models.User{},
         &models.Category{},
         &models.Task{},
+        &models.ChecklistItem{},
+        &models.TaskReminder{},
         &models.ExternalDataSync{},
+        &models.Session{},
+        &models.APIKey{},
+        &models.Notification{},
+        &models.DeviceToken{},
+        &models.DigestLog{},
+        &models.WebhookSubscription{},
+        &models.WebhookDelivery{},
+        &models.OutboxEvent{},
+        &models.ProcessedEvent{},
+        &models.AuditLog{},
+        &models.TaskChange{},
+        &models.SchemaMigration{},
+        &models.PendingStatusNotification{},
+        &models.TaskReminderSend{},
     )
     if err != nil {
         log.Fatal("❌ Failed to migrate database:", err)
     }
+    migrateTaskSearch()
+    migrateUserEmails()
+    migrateLegacyFCMTokens()
+    migrateWebhookResponseBodies()
+    runDataMigration("backfill_task_reminders", backfillTaskReminders)
+    runDataMigration("backfill_task_reminder_sends", backfillTaskReminderSends)
     log.Println("✅ Database migrations completed")
     
     seedDefaultCategories()
+    bootstrapAdminUsers()
     
     log.Println("⚙️  Starting background workers...")
     taskReminderWorker := workers.NewTaskReminderWorker()
@@ -45,6 +80,22 @@ func main() {
     weatherSyncWorker := workers.NewWeatherSyncWorker()
     weatherSyncWorker.Start()
     
+    recurringTaskWorker := workers.NewRecurringTaskWorker()
+    recurringTaskWorker.Start()
+    
+    digestWorker := workers.NewDigestWorker()
+    digestWorker.Start()
+
+    webhookWorker := workers.NewWebhookWorker()
+    webhookWorker.Start()
+
+    statusNotificationWorker := workers.NewStatusNotificationWorker()
+    statusNotificationWorker.Start()
+
+    registerOutboxSubscribers()
+    outboxWorker := workers.NewOutboxWorker()
+    outboxWorker.Start()
+    
     router := routes.SetupRoutes()
     
     // Start server
@@ -57,8 +108,12 @@ func main() {
     log.Printf("🔗 API Base URL: http://localhost:%s/api", port)
     log.Printf("🔗 Health Check: http://localhost:%s/health", port)
     
+    server := &http.Server{
+        Addr:    ":" + port,
+        Handler: router,
+    }
     go func() {
-        if err := router.Run(":" + port); err != nil {
+        if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
             log.Fatal("❌ Failed to start server:", err)
         }
     }()
@@ -68,8 +123,23 @@ func main() {
     <-quit
     
     log.Println("🛑 Shutting down server...")
+    
+    // Stream SSE dan WebSocket ditutup dulu, kalau tidak Shutdown menunggu koneksi yang tidak pernah selesai
+    services.Events().Close()
+    services.Boards().Close()
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    if err := server.Shutdown(ctx); err != nil {
+        log.Printf("⚠️  HTTP server shutdown: %v", err)
+    }
+    
     taskReminderWorker.Stop()
     weatherSyncWorker.Stop()
+    recurringTaskWorker.Stop()
+    digestWorker.Stop()
+    outboxWorker.Stop()
+    webhookWorker.Stop()
+    statusNotificationWorker.Stop()
     log.Println("✅ Server stopped gracefully")
 }
 
@@ -100,4 +170,171 @@ func seedDefaultCategories() {
             log.Printf("✅ Created category: %s", category.Name)
         }
     }
-}
\ No newline at end of file
+}
+
+// bootstrapAdminUsers memberi role admin ke Firebase UID yang terdaftar di ADMIN_FIREBASE_UIDS (dipisah koma).
+// Email tidak dipakai karena bisa diisi bebas oleh user tanpa verifikasi, sedangkan Firebase UID
+// hanya berasal dari token yang sudah diverifikasi
+func bootstrapAdminUsers() {
+    if os.Getenv("ADMIN_EMAILS") != "" {
+        log.Println("⚠️  ADMIN_EMAILS is no longer supported, use ADMIN_FIREBASE_UIDS instead")
+    }
+    
+    adminUIDs := os.Getenv("ADMIN_FIREBASE_UIDS")
+    if adminUIDs == "" {
+        return
+    }
+    
+    for _, uid := range strings.Split(adminUIDs, ",") {
+        uid = strings.TrimSpace(uid)
+        if uid == "" {
+            continue
+        }
+        
+        result := config.DB.Model(&models.User{}).
+            Where("firebase_uid = ? AND role <> ?", uid, models.RoleAdmin).
+            Update("role", models.RoleAdmin)
+        if result.Error != nil {
+            log.Printf("⚠️  Error promoting %s to admin: %v", uid, result.Error)
+        } else if result.RowsAffected > 0 {
+            log.Printf("👑 Promoted %s to admin", uid)
+        }
+    }
+}
+
+// migrateTaskSearch membuat kolom tsvector (generated, otomatis ter-update saat task ditulis)
+// beserta GIN index untuk full-text search task
+func migrateTaskSearch() {
+    statements := []string{
+        `ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+            GENERATED ALWAYS AS (
+                setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+                setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+            ) STORED`,
+        `CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector)`,
+    }
+    
+    for _, statement := range statements {
+        if err := config.DB.Exec(statement).Error; err != nil {
+            log.Fatal("❌ Failed to migrate task search:", err)
+        }
+    }
+}
+
+// migrateUserEmails menormalkan email lama (lowercase, tanpa spasi) lalu memasang unique index pada
+// LOWER(email), supaya "Budi@x.com" dan "budi@x.com" tidak bisa menjadi dua akun
+func migrateUserEmails() {
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec(`UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email))`).Error; err != nil {
+            return err
+        }
+        return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email))`).Error
+    })
+    if err != nil {
+        log.Fatal("❌ Failed to migrate user emails (accounts whose emails differ only by case must be merged first):", err)
+    }
+}
+
+// migrateLegacyFCMTokens memindahkan users.fcm_token (satu token per user) ke tabel device_tokens.
+// Kolom lama dikosongkan supaya token yang sudah dihapus karena tidak valid tidak ikut kembali.
+func migrateLegacyFCMTokens() {
+    if !config.DB.Migrator().HasColumn("users", "fcm_token") {
+        return
+    }
+    
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        err := tx.Exec(`INSERT INTO device_tokens (user_id, token, platform, user_agent, last_seen_at, created_at, updated_at)
+            SELECT id, fcm_token, 'web', '', updated_at, NOW(), NOW() FROM users
+            WHERE fcm_token IS NOT NULL AND fcm_token <> '' AND deleted_at IS NULL
+            ON CONFLICT (token) DO NOTHING`).Error
+        if err != nil {
+            return err
+        }
+        return tx.Exec(`UPDATE users SET fcm_token = '' WHERE fcm_token IS NOT NULL AND fcm_token <> ''`).Error
+    })
+    if err != nil {
+        log.Fatal("❌ Failed to migrate FCM tokens:", err)
+    }
+}
+
+// migrateWebhookResponseBodies menghapus isi response webhook yang dulu disimpan; isi response dari URL milik user
+// bisa berasal dari layanan internal dan tidak boleh bisa dibaca lagi
+func migrateWebhookResponseBodies() {
+    if !config.DB.Migrator().HasColumn("webhook_deliveries", "response_body") {
+        return
+    }
+    if err := config.DB.Migrator().DropColumn("webhook_deliveries", "response_body"); err != nil {
+        log.Fatal("❌ Failed to migrate webhook deliveries:", err)
+    }
+}
+
+// runDataMigration menjalankan migrasi data satu kali; penandanya ditulis di transaksi yang sama
+func runDataMigration(name string, migrate func(tx *gorm.DB) error) {
+    var count int64
+    config.DB.Model(&models.SchemaMigration{}).Where("name = ?", name).Count(&count)
+    if count > 0 {
+        return
+    }
+
+    err := config.DB.Transaction(func(tx *gorm.DB) error {
+        if err := migrate(tx); err != nil {
+            return err
+        }
+        return tx.Create(&models.SchemaMigration{Name: name, AppliedAt: time.Now()}).Error
+    })
+    if err != nil {
+        log.Fatal("❌ Failed to run data migration "+name+":", err)
+    }
+    log.Printf("✅ Data migration %s applied", name)
+}
+
+// backfillTaskReminderSends mencatat reminder yang sudah terkirim sebelum task_reminder_sends ada;
+// hanya pengiriman terakhir yang masih diketahui dari task_reminders.sent_at
+func backfillTaskReminderSends(tx *gorm.DB) error {
+    return tx.Exec(`INSERT INTO task_reminder_sends (task_id, reminder_id, offset_minutes, sent_at)
+        SELECT task_id, id, offset_minutes, sent_at FROM task_reminders WHERE sent_at IS NOT NULL`).Error
+}
+
+// backfillTaskReminders membuat jadwal reminder (default offset milik user) untuk task dengan deadline
+// di masa depan yang dibuat sebelum tabel task_reminders ada. Reminder lama yang sudah terkirim
+// (tasks.reminder_sent_at) tidak dikirim ulang.
+func backfillTaskReminders(tx *gorm.DB) error {
+    reminderService := services.NewReminderService()
+
+    // Task yang dibuat setelah reminder pertama tercatat sudah melewati alur baru; daftar reminder
+    // kosong di task tersebut berarti user sengaja mematikannya
+    query := tx.Preload("User").
+        Where("deadline > ? AND status <> ?", time.Now(), "done").
+        Where("NOT EXISTS (SELECT 1 FROM task_reminders WHERE task_reminders.task_id = tasks.id)").
+        Where("NOT EXISTS (SELECT 1 FROM task_reminders WHERE task_reminders.created_at <= tasks.created_at)")
+
+    var tasks []models.Task
+    return query.FindInBatches(&tasks, 200, func(*gorm.DB, int) error {
+        for _, task := range tasks {
+            if err := reminderService.SetTaskReminders(tx, task, reminderService.OffsetsForUser(task.User)); err != nil {
+                return err
+            }
+            if task.ReminderSentAt != nil {
+                err := tx.Model(&models.TaskReminder{}).
+                    Where("task_id = ? AND remind_at <= ?", task.ID, *task.ReminderSentAt).
+                    Update("sent_at", *task.ReminderSentAt).Error
+                if err != nil {
+                    return err
+                }
+            }
+        }
+        log.Printf("⏰ Backfilled reminders for %d tasks", len(tasks))
+        return nil
+    }).Error
+}
+
+// registerOutboxSubscribers - consumer domain event. Nama consumer dipakai sebagai kunci di processed_events,
+// jadi jangan diganti tanpa migrasi. Hub SSE dan WebSocket hanya menjangkau client di instance ini,
+// jadi keduanya memakai broadcast (setiap instance menerima setiap event), bukan klaim global.
+func registerOutboxSubscribers() {
+    outbox := services.Outbox()
+    outbox.Subscribe("webhooks", services.NewWebhookService().HandleOutboxEvent, models.WebhookEvents...)
+    outbox.Subscribe("status_notifications", services.StatusNotifications().HandleOutboxEvent, models.EventTaskStatusChanged)
+    outbox.SubscribeBroadcast("live_events", services.Events().HandleOutboxEvent, services.LiveEvents...)
+    outbox.SubscribeBroadcast("boards", services.Boards().HandleOutboxEvent, services.BoardEvents...)
+}
@@ -0,0 +1,350 @@
+package middleware
+
+import (
+    "context"
+    "crypto/rsa"
+    "errors"
+    "fmt"
+    "net/http"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "firebase.google.com/go/v4/auth"
+    "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+    ContextUserKey        = "currentUser"
+    ContextFirebaseUIDKey = "firebaseUID"
+    ContextSessionKey     = "currentSession"
+    ContextAPIKeyKey      = "currentAPIKey"
+    ContextAuthTimeKey    = "authTime"
+)
+
+// VerifiedToken - hasil verifikasi ID token: UID pemilik dan waktu user terakhir kali login (claim auth_time)
+type VerifiedToken struct {
+    UID      string
+    AuthTime time.Time
+}
+
+// TokenVerifier memverifikasi ID token dan mengembalikan Firebase UID pemiliknya
+type TokenVerifier interface {
+    VerifyIDToken(ctx context.Context, idToken string) (VerifiedToken, error)
+}
+
+// FirebaseTokenVerifier - verifikasi token lewat Firebase Admin SDK
+type FirebaseTokenVerifier struct {
+    client *auth.Client
+}
+
+func NewFirebaseTokenVerifier(client *auth.Client) *FirebaseTokenVerifier {
+    return &FirebaseTokenVerifier{client: client}
+}
+
+func (v *FirebaseTokenVerifier) VerifyIDToken(ctx context.Context, idToken string) (VerifiedToken, error) {
+    if v.client == nil {
+        return VerifiedToken{}, errors.New("firebase auth is not configured")
+    }
+
+    token, err := v.client.VerifyIDToken(ctx, idToken)
+    if err != nil {
+        return VerifiedToken{}, err
+    }
+    return VerifiedToken{UID: token.UID, AuthTime: time.Unix(token.AuthTime, 0)}, nil
+}
+
+// KeySetTokenVerifier - verifikasi token RS256 dengan public key lokal (untuk testing / emulator)
+type KeySetTokenVerifier struct {
+    ProjectID string
+    Keys      map[string]*rsa.PublicKey
+}
+
+func NewKeySetTokenVerifier(projectID string, keys map[string]*rsa.PublicKey) *KeySetTokenVerifier {
+    return &KeySetTokenVerifier{ProjectID: projectID, Keys: keys}
+}
+
+// idTokenClaims - claim standar ditambah auth_time seperti pada ID token Firebase
+type idTokenClaims struct {
+    jwt.RegisteredClaims
+    AuthTime int64 `json:"auth_time"`
+}
+
+func (v *KeySetTokenVerifier) VerifyIDToken(ctx context.Context, idToken string) (VerifiedToken, error) {
+    claims := &idTokenClaims{}
+    _, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+        if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+            return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+        }
+        kid, _ := token.Header["kid"].(string)
+        key, ok := v.Keys[kid]
+        if !ok {
+            return nil, fmt.Errorf("unknown key id: %q", kid)
+        }
+        return key, nil
+    })
+    if err != nil {
+        return VerifiedToken{}, err
+    }
+
+    if !claims.VerifyAudience(v.ProjectID, true) {
+        return VerifiedToken{}, errors.New("invalid token audience")
+    }
+    if !claims.VerifyIssuer("https://securetoken.google.com/"+v.ProjectID, true) {
+        return VerifiedToken{}, errors.New("invalid token issuer")
+    }
+    if claims.Subject == "" {
+        return VerifiedToken{}, errors.New("token has no subject")
+    }
+    return VerifiedToken{UID: claims.Subject, AuthTime: time.Unix(claims.AuthTime, 0)}, nil
+}
+
+// VerifyToken hanya memverifikasi token, tanpa mewajibkan user sudah terdaftar di database
+func VerifyToken(verifier TokenVerifier) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        token, ok := bearerToken(c)
+        if !ok {
+            return
+        }
+
+        verified, ok := verifyIDToken(c, verifier, token)
+        if !ok {
+            return
+        }
+
+        c.Set(ContextFirebaseUIDKey, verified.UID)
+        c.Next()
+    }
+}
+
+// AuthRequired memverifikasi token dan me-resolve user pemiliknya, baik lewat
+// personal API key, session token native maupun Firebase ID token (via FirebaseUID)
+func AuthRequired(verifier TokenVerifier) gin.HandlerFunc {
+    authService := services.NewAuthService()
+    apiKeyService := services.NewAPIKeyService()
+
+    return func(c *gin.Context) {
+        token := c.GetHeader("X-API-Key")
+        if token == "" {
+            var ok bool
+            token, ok = bearerToken(c)
+            if !ok {
+                return
+            }
+        }
+
+        if strings.HasPrefix(token, services.APIKeyPrefix) {
+            apiKey, err := apiKeyService.ResolveKey(token)
+            if err != nil {
+                c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+                    "error": "Invalid or expired API key",
+                    "details": err.Error(),
+                    "success": false,
+                })
+                return
+            }
+
+            // Tanpa scope admin, key hanya boleh mengakses data pemiliknya sendiri
+            user := apiKey.User
+            if !apiKey.HasScope(models.ScopeAdmin) {
+                user.Role = models.RoleMember
+            }
+
+            c.Set(ContextAPIKeyKey, apiKey)
+            c.Set(ContextUserKey, user)
+            c.Next()
+            return
+        }
+
+        if strings.HasPrefix(token, services.SessionTokenPrefix) {
+            session, err := authService.ResolveSession(token)
+            if err != nil {
+                c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+                    "error": "Invalid or expired token",
+                    "details": err.Error(),
+                    "success": false,
+                })
+                return
+            }
+
+            c.Set(ContextSessionKey, session)
+            c.Set(ContextAuthTimeKey, session.CreatedAt)
+            c.Set(ContextUserKey, session.User)
+            c.Next()
+            return
+        }
+
+        verified, ok := verifyIDToken(c, verifier, token)
+        if !ok {
+            return
+        }
+
+        var user models.User
+        if err := config.DB.Where("firebase_uid = ?", verified.UID).First(&user).Error; err != nil {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+                "error": "User not registered",
+                "success": false,
+            })
+            return
+        }
+
+        c.Set(ContextFirebaseUIDKey, verified.UID)
+        c.Set(ContextAuthTimeKey, verified.AuthTime)
+        c.Set(ContextUserKey, user)
+        c.Next()
+    }
+}
+
+// CurrentUser mengambil user yang sudah diautentikasi dari context
+func CurrentUser(c *gin.Context) (models.User, bool) {
+    value, exists := c.Get(ContextUserKey)
+    if !exists {
+        return models.User{}, false
+    }
+    user, ok := value.(models.User)
+    return user, ok
+}
+
+// CurrentFirebaseUID mengambil Firebase UID hasil verifikasi token dari context
+func CurrentFirebaseUID(c *gin.Context) string {
+    return c.GetString(ContextFirebaseUIDKey)
+}
+
+// CurrentSession mengambil session native dari context (tidak ada untuk login Firebase)
+func CurrentSession(c *gin.Context) (models.Session, bool) {
+    value, exists := c.Get(ContextSessionKey)
+    if !exists {
+        return models.Session{}, false
+    }
+    session, ok := value.(models.Session)
+    return session, ok
+}
+
+// CurrentAuthTime - kapan user terakhir kali login: auth_time token Firebase atau waktu session native dibuat.
+// Tidak ada untuk request dengan API key.
+func CurrentAuthTime(c *gin.Context) (time.Time, bool) {
+    value, exists := c.Get(ContextAuthTimeKey)
+    if !exists {
+        return time.Time{}, false
+    }
+    authTime, ok := value.(time.Time)
+    return authTime, ok && !authTime.IsZero()
+}
+
+// CurrentAPIKey mengambil API key dari context jika request diautentikasi dengan API key
+func CurrentAPIKey(c *gin.Context) (models.APIKey, bool) {
+    value, exists := c.Get(ContextAPIKeyKey)
+    if !exists {
+        return models.APIKey{}, false
+    }
+    apiKey, ok := value.(models.APIKey)
+    return apiKey, ok
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+    header := c.GetHeader("Authorization")
+    if !strings.HasPrefix(header, "Bearer ") {
+        c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+            "error": "Missing bearer token",
+            "success": false,
+        })
+        return "", false
+    }
+
+    return strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")), true
+}
+
+func verifyIDToken(c *gin.Context, verifier TokenVerifier, idToken string) (VerifiedToken, bool) {
+    verified, err := verifier.VerifyIDToken(c.Request.Context(), idToken)
+    if err != nil {
+        c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+            "error": "Invalid or expired token",
+            "details": err.Error(),
+            "success": false,
+        })
+        return VerifiedToken{}, false
+    }
+
+    return verified, true
+}
+
+// HasPermission mengecek permission user yang sedang login, untuk dipakai di dalam handler
+func HasPermission(c *gin.Context, permission string) bool {
+    user, ok := CurrentUser(c)
+    return ok && user.Can(permission)
+}
+
+// RequirePermission menolak request jika user yang login tidak memiliki permission tersebut
+func RequirePermission(permission string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if !HasPermission(c, permission) {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+                "error": "Insufficient permissions",
+                "success": false,
+            })
+            return
+        }
+        c.Next()
+    }
+}
+
+// RequireScope membatasi request yang memakai API key ke scope tertentu;
+// request dengan Firebase ID token atau session token tidak dibatasi scope
+func RequireScope(scope string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if apiKey, ok := CurrentAPIKey(c); ok && !apiKey.HasScope(scope) {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+                "error": "API key is missing the required scope",
+                "details": scope,
+                "success": false,
+            })
+            return
+        }
+        c.Next()
+    }
+}
+
+// RequireInteractiveAuth menolak request yang memakai API key (mis. untuk mengelola API key itu sendiri)
+func RequireInteractiveAuth() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if _, ok := CurrentAPIKey(c); ok {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+                "error": "This endpoint cannot be used with an API key",
+                "success": false,
+            })
+            return
+        }
+        c.Next()
+    }
+}
+
+// TokenFromQuery - EventSource di browser tidak bisa mengirim header Authorization, jadi token boleh
+// dikirim lewat query string. Hanya dipasang di route stream, sebelum AuthRequired.
+// Token dihapus dari URL setelah dibaca, dan API key (berumur panjang) ditolak di sini.
+func TokenFromQuery(param string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        query := c.Request.URL.Query()
+        token := query.Get(param)
+        if token == "" {
+            c.Next()
+            return
+        }
+        query.Del(param)
+        c.Request.URL.RawQuery = query.Encode()
+
+        if strings.HasPrefix(token, services.APIKeyPrefix) {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+                "error": "API keys must be sent in the X-API-Key or Authorization header",
+                "success": false,
+            })
+            return
+        }
+        if c.GetHeader("Authorization") == "" && c.GetHeader("X-API-Key") == "" {
+            c.Request.Header.Set("Authorization", "Bearer "+token)
+        }
+        c.Next()
+    }
+}
@@ -0,0 +1,355 @@
+package middleware
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "database/sql/driver"
+    "encoding/hex"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/internal/fakedb"
+    "taskflow-api/models"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v4"
+)
+
+const testProjectID = "taskflow-test"
+
+type testKeys struct {
+    signing  *rsa.PrivateKey
+    verifier *KeySetTokenVerifier
+}
+
+func newTestKeys(t *testing.T) testKeys {
+    t.Helper()
+    key, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        t.Fatalf("GenerateKey() = %v", err)
+    }
+    return testKeys{
+        signing:  key,
+        verifier: NewKeySetTokenVerifier(testProjectID, map[string]*rsa.PublicKey{"key-1": &key.PublicKey}),
+    }
+}
+
+type tokenClaims struct {
+    jwt.RegisteredClaims
+    AuthTime int64 `json:"auth_time,omitempty"`
+}
+
+// validClaims - claim seperti ID token Firebase asli milik uid
+func validClaims(uid string) tokenClaims {
+    now := time.Now()
+    return tokenClaims{
+        RegisteredClaims: jwt.RegisteredClaims{
+            Issuer:    "https://securetoken.google.com/" + testProjectID,
+            Audience:  jwt.ClaimStrings{testProjectID},
+            Subject:   uid,
+            IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+            ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+        },
+        AuthTime: now.Add(-2 * time.Minute).Unix(),
+    }
+}
+
+func (k testKeys) sign(t *testing.T, kid string, claims tokenClaims) string {
+    t.Helper()
+    token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+    token.Header["kid"] = kid
+    signed, err := token.SignedString(k.signing)
+    if err != nil {
+        t.Fatalf("SignedString() = %v", err)
+    }
+    return signed
+}
+
+func TestKeySetTokenVerifierAcceptsValidToken(t *testing.T) {
+    keys := newTestKeys(t)
+    claims := validClaims("firebase-uid-1")
+
+    verified, err := keys.verifier.VerifyIDToken(context.Background(), keys.sign(t, "key-1", claims))
+    if err != nil {
+        t.Fatalf("VerifyIDToken() = %v", err)
+    }
+    if verified.UID != "firebase-uid-1" {
+        t.Errorf("UID = %q, want firebase-uid-1", verified.UID)
+    }
+    if verified.AuthTime.Unix() != claims.AuthTime {
+        t.Errorf("AuthTime = %v, want %v", verified.AuthTime.Unix(), claims.AuthTime)
+    }
+}
+
+func TestKeySetTokenVerifierRejectsInvalidTokens(t *testing.T) {
+    keys := newTestKeys(t)
+    otherKeys := newTestKeys(t)
+
+    expired := validClaims("uid")
+    expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+    wrongAudience := validClaims("uid")
+    wrongAudience.Audience = jwt.ClaimStrings{"other-project"}
+    wrongIssuer := validClaims("uid")
+    wrongIssuer.Issuer = "https://securetoken.google.com/other-project"
+    noSubject := validClaims("")
+
+    hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims("uid")).SignedString([]byte("secret"))
+    if err != nil {
+        t.Fatalf("SignedString() = %v", err)
+    }
+
+    tests := []struct {
+        name  string
+        token string
+    }{
+        {"expired", keys.sign(t, "key-1", expired)},
+        {"wrong audience", keys.sign(t, "key-1", wrongAudience)},
+        {"wrong issuer", keys.sign(t, "key-1", wrongIssuer)},
+        {"no subject", keys.sign(t, "key-1", noSubject)},
+        {"unknown key id", keys.sign(t, "key-2", validClaims("uid"))},
+        {"signed by another key", otherKeys.sign(t, "key-1", validClaims("uid"))},
+        {"hmac signature", hmacToken},
+        {"malformed", "not-a-token"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := keys.verifier.VerifyIDToken(context.Background(), tt.token); err == nil {
+                t.Error("VerifyIDToken() = nil, want error")
+            }
+        })
+    }
+}
+
+// useFakeUsers mengganti config.DB dengan database palsu yang hanya mengenal user dengan firebase_uid "registered"
+func useFakeUsers(t *testing.T) {
+    t.Helper()
+    db, _ := fakedb.Open(t, func(query string, args []driver.Value) fakedb.Result {
+        if strings.Contains(query, `FROM "users"`) && len(args) > 0 && fmt.Sprint(args[0]) == "registered" {
+            return fakedb.Result{
+                Columns: []string{"id", "name", "email", "firebase_uid", "role"},
+                Rows:    [][]driver.Value{{int64(7), "Registered", "registered@example.com", "registered", models.RoleMember}},
+            }
+        }
+        return fakedb.Result{}
+    })
+
+    previous := config.DB
+    config.DB = db
+    t.Cleanup(func() { config.DB = previous })
+}
+
+func TestAuthRequired(t *testing.T) {
+    useFakeUsers(t)
+    keys := newTestKeys(t)
+    gin.SetMode(gin.TestMode)
+
+    router := gin.New()
+    router.GET("/me", AuthRequired(keys.verifier), func(c *gin.Context) {
+        user, _ := CurrentUser(c)
+        authTime, _ := CurrentAuthTime(c)
+        c.JSON(http.StatusOK, gin.H{"id": user.ID, "uid": CurrentFirebaseUID(c), "auth_time": authTime.Unix()})
+    })
+
+    tests := []struct {
+        name          string
+        authorization string
+        wantStatus    int
+    }{
+        {"registered user", "Bearer " + keys.sign(t, "key-1", validClaims("registered")), http.StatusOK},
+        {"unregistered user", "Bearer " + keys.sign(t, "key-1", validClaims("stranger")), http.StatusUnauthorized},
+        {"missing header", "", http.StatusUnauthorized},
+        {"not a bearer token", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+        {"invalid token", "Bearer not-a-token", http.StatusUnauthorized},
+        {"token from another project", "Bearer " + keys.sign(t, "key-1", func() tokenClaims {
+            claims := validClaims("registered")
+            claims.Audience = jwt.ClaimStrings{"other-project"}
+            return claims
+        }()), http.StatusUnauthorized},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            recorder := httptest.NewRecorder()
+            request := httptest.NewRequest(http.MethodGet, "/me", nil)
+            if tt.authorization != "" {
+                request.Header.Set("Authorization", tt.authorization)
+            }
+            router.ServeHTTP(recorder, request)
+
+            if recorder.Code != tt.wantStatus {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+            }
+            if tt.wantStatus == http.StatusOK && !strings.Contains(recorder.Body.String(), `"id":7`) {
+                t.Errorf("body = %s, want user 7 in context", recorder.Body)
+            }
+        })
+    }
+}
+
+func TestRequireScopeAndInteractiveAuth(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    tests := []struct {
+        name       string
+        apiKey     *models.APIKey
+        handler    gin.HandlerFunc
+        wantStatus int
+    }{
+        {"session without scope check", nil, RequireScope(models.ScopeExport), http.StatusOK},
+        {"key with scope", &models.APIKey{Scopes: []string{models.ScopeExport}}, RequireScope(models.ScopeExport), http.StatusOK},
+        {"key without scope", &models.APIKey{Scopes: []string{models.ScopeTasksRead}}, RequireScope(models.ScopeExport), http.StatusForbidden},
+        {"interactive session", nil, RequireInteractiveAuth(), http.StatusOK},
+        {"interactive with key", &models.APIKey{Scopes: []string{models.ScopeAdmin}}, RequireInteractiveAuth(), http.StatusForbidden},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            router := gin.New()
+            router.GET("/", func(c *gin.Context) {
+                if tt.apiKey != nil {
+                    c.Set(ContextAPIKeyKey, *tt.apiKey)
+                }
+                c.Next()
+            }, tt.handler, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+            recorder := httptest.NewRecorder()
+            router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+            if recorder.Code != tt.wantStatus {
+                t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+            }
+        })
+    }
+}
+
+func TestTokenFromQuery(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    tests := []struct {
+        name         string
+        target       string
+        wantStatus   int
+        wantAuth     string
+        wantRawQuery string
+    }{
+        {"session token", "/events?access_token=abc&last_event_id=5", http.StatusOK, "Bearer abc", "last_event_id=5"},
+        {"no token", "/events?last_event_id=5", http.StatusOK, "", "last_event_id=5"},
+        {"api key", "/events?access_token=tf_key_secret", http.StatusUnauthorized, "", ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var gotAuth, gotRawQuery string
+            router := gin.New()
+            router.GET("/events", TokenFromQuery("access_token"), func(c *gin.Context) {
+                gotAuth = c.GetHeader("Authorization")
+                gotRawQuery = c.Request.URL.RawQuery
+                c.Status(http.StatusOK)
+            })
+
+            recorder := httptest.NewRecorder()
+            router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+            if recorder.Code != tt.wantStatus {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+            }
+            if gotAuth != tt.wantAuth {
+                t.Errorf("Authorization = %q, want %q", gotAuth, tt.wantAuth)
+            }
+            if gotRawQuery != tt.wantRawQuery {
+                t.Errorf("RawQuery = %q, want %q", gotRawQuery, tt.wantRawQuery)
+            }
+        })
+    }
+}
+
+func TestRedactPath(t *testing.T) {
+    tests := []struct {
+        path string
+        want string
+    }{
+        {"/api/tasks", "/api/tasks"},
+        {"/api/tasks?status=done", "/api/tasks?status=done"},
+        {"/api/me/events?access_token=secret&last_event_id=5", "/api/me/events?access_token=REDACTED&last_event_id=5"},
+        {"/api/me/events?access_token=%zz", "/api/me/events"},
+    }
+    for _, tt := range tests {
+        if got := redactPath(tt.path); got != tt.want {
+            t.Errorf("redactPath(%q) = %q, want %q", tt.path, got, tt.want)
+        }
+    }
+}
+
+// useFakeAPIKey - database palsu dengan satu API key aktif milik admin 9; key lain dianggap dicabut / kedaluwarsa
+func useFakeAPIKey(t *testing.T, key string, scopes string) {
+    t.Helper()
+    sum := sha256.Sum256([]byte(key))
+    keyHash := hex.EncodeToString(sum[:])
+
+    db, _ := fakedb.Open(t, func(query string, args []driver.Value) fakedb.Result {
+        switch {
+        case strings.Contains(query, `FROM "api_keys"`) && len(args) > 0 && args[0] == keyHash:
+            if !strings.Contains(query, "revoked_at IS NULL") || !strings.Contains(query, "expires_at IS NULL OR expires_at >") {
+                t.Errorf("api key lookup does not filter revoked or expired keys: %s", query)
+            }
+            return fakedb.Result{
+                Columns: []string{"id", "user_id", "name", "prefix", "key_hash", "scopes"},
+                Rows:    [][]driver.Value{{int64(1), int64(9), "script", key[:13], keyHash, scopes}},
+            }
+        case strings.Contains(query, `FROM "users"`):
+            return fakedb.Result{
+                Columns: []string{"id", "name", "email", "role"},
+                Rows:    [][]driver.Value{{int64(9), "Admin", "admin@example.com", models.RoleAdmin}},
+            }
+        }
+        return fakedb.Result{}
+    })
+
+    previous := config.DB
+    config.DB = db
+    t.Cleanup(func() { config.DB = previous })
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    const key = "tf_key_readonlykey"
+
+    tests := []struct {
+        name       string
+        key        string
+        scopes     string
+        scope      string
+        wantStatus int
+        wantRole   string
+    }{
+        {"scope granted", key, `["tasks:read"]`, models.ScopeTasksRead, http.StatusOK, models.RoleMember},
+        {"scope missing", key, `["tasks:read"]`, models.ScopeTasksWrite, http.StatusForbidden, ""},
+        // Tanpa scope admin, role admin pemilik key tidak ikut terbawa
+        {"admin scope keeps role", key, `["tasks:read","admin"]`, models.ScopeTasksRead, http.StatusOK, models.RoleAdmin},
+        {"unknown, revoked or expired key", "tf_key_other", `["tasks:read"]`, models.ScopeTasksRead, http.StatusUnauthorized, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            useFakeAPIKey(t, key, tt.scopes)
+
+            router := gin.New()
+            router.GET("/tasks", AuthRequired(&KeySetTokenVerifier{}), RequireScope(tt.scope), func(c *gin.Context) {
+                user, _ := CurrentUser(c)
+                c.JSON(http.StatusOK, gin.H{"role": user.Role})
+            })
+
+            recorder := httptest.NewRecorder()
+            request := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+            request.Header.Set("X-API-Key", tt.key)
+            router.ServeHTTP(recorder, request)
+
+            if recorder.Code != tt.wantStatus {
+                t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+            }
+            if tt.wantRole != "" && !strings.Contains(recorder.Body.String(), `"role":"`+tt.wantRole+`"`) {
+                t.Errorf("body = %s, want role %s", recorder.Body, tt.wantRole)
+            }
+        })
+    }
+}
@@ -10,8 +10,8 @@ func CORSMiddleware() gin.HandlerFunc {
     config := cors.Config{
         AllowOrigins:     []string{"http://localhost:3000", "http://localhost:8000", "http://127.0.0.1:3000"},
         AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-        AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With"},
-        ExposeHeaders:    []string{"Content-Length", "X-Status-Change"},
+        AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With", "X-Request-ID", "X-Audit-Reason"},
+        ExposeHeaders:    []string{"Content-Length", "X-Status-Change", "X-Request-ID"},
         AllowCredentials: true,
         MaxAge:          12 * time.Hour,
     }
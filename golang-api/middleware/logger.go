@@ -2,6 +2,8 @@ package middleware
 
 import (
     "fmt"
+    "net/url"
+    "strings"
     "time"
     
     "github.com/gin-gonic/gin"
@@ -26,8 +28,34 @@ func Logger() gin.HandlerFunc {
             param.Latency,
             param.ClientIP,
             methodColor, param.Method, resetColor,
-            param.Path,
+            redactPath(param.Path),
             param.ErrorMessage,
         )
     })
-}
\ No newline at end of file
+}
+// sensitiveQueryParams tidak boleh tercatat di access log. Gin menyimpan query string sebelum handler
+// berjalan, jadi token yang dihapus TokenFromQuery tetap harus disamarkan di sini
+var sensitiveQueryParams = []string{"access_token"}
+
+func redactPath(path string) string {
+    base, rawQuery, found := strings.Cut(path, "?")
+    if !found {
+        return path
+    }
+    query, err := url.ParseQuery(rawQuery)
+    if err != nil {
+        return base
+    }
+
+    redacted := false
+    for _, param := range sensitiveQueryParams {
+        if query.Has(param) {
+            query.Set(param, "REDACTED")
+            redacted = true
+        }
+    }
+    if !redacted {
+        return path
+    }
+    return base + "?" + query.Encode()
+}
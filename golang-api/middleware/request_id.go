@@ -0,0 +1,38 @@
+package middleware
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+
+    "github.com/gin-gonic/gin"
+)
+
+const (
+    RequestIDHeader     = "X-Request-ID"
+    ContextRequestIDKey = "requestID"
+)
+
+// RequestID memakai X-Request-ID dari client/proxy jika ada, atau membuat yang baru,
+// lalu mengembalikannya di response supaya request bisa dilacak di log dan audit log
+func RequestID() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        requestID := c.GetHeader(RequestIDHeader)
+        if requestID == "" || len(requestID) > 128 {
+            requestID = newRequestID()
+        }
+        c.Set(ContextRequestIDKey, requestID)
+        c.Header(RequestIDHeader, requestID)
+        c.Next()
+    }
+}
+
+// GetRequestID mengembalikan ID request yang di-set oleh RequestID
+func GetRequestID(c *gin.Context) string {
+    return c.GetString(ContextRequestIDKey)
+}
+
+func newRequestID() string {
+    buf := make([]byte, 16)
+    rand.Read(buf)
+    return hex.EncodeToString(buf)
+}
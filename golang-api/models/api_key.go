@@ -0,0 +1,45 @@
+package models
+
+import (
+    "time"
+)
+
+// APIKey - personal API key untuk script / integrasi, disimpan sebagai hash SHA-256
+type APIKey struct {
+    ID         uint       `json:"id" gorm:"primaryKey"`
+    UserID     uint       `json:"user_id" gorm:"not null;index"`
+    Name       string     `json:"name" gorm:"not null"`
+    Prefix     string     `json:"prefix" gorm:"not null"`
+    KeyHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+    Scopes     []string   `json:"scopes" gorm:"serializer:json;type:text"`
+    LastUsedAt *time.Time `json:"last_used_at"`
+    ExpiresAt  *time.Time `json:"expires_at"`
+    RevokedAt  *time.Time `json:"revoked_at"`
+    CreatedAt  time.Time  `json:"created_at"`
+    
+    User       User       `json:"-" gorm:"foreignKey:UserID"`
+}
+
+const (
+    ScopeTasksRead  = "tasks:read"
+    ScopeTasksWrite = "tasks:write"
+    ScopeExport     = "export"
+    ScopeAdmin      = "admin"
+)
+
+var ValidScopes = []string{ScopeTasksRead, ScopeTasksWrite, ScopeExport, ScopeAdmin}
+
+func (k APIKey) HasScope(scope string) bool {
+    for _, s := range k.Scopes {
+        if s == scope {
+            return true
+        }
+    }
+    return false
+}
+
+type CreateAPIKeyRequest struct {
+    Name      string     `json:"name" binding:"required"`
+    Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write export admin"`
+    ExpiresAt *time.Time `json:"expires_at"`
+}
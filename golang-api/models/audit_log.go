@@ -0,0 +1,48 @@
+package models
+
+import (
+    "time"
+)
+
+// AuditLog - catatan setiap perubahan task, user dan kategori: siapa, apa, kapan, dari mana dan field apa saja
+type AuditLog struct {
+    ID         uint                   `json:"id" gorm:"primaryKey"`
+    ActorID    uint                   `json:"actor_id" gorm:"index"`        // 0 = sistem / registrasi
+    ActorEmail string                 `json:"actor_email"`                  // disalin supaya tetap terbaca walau user dihapus
+    Action     string                 `json:"action" gorm:"not null;index"` // mis. task.update
+    EntityType string                 `json:"entity_type" gorm:"not null;index:idx_audit_entity"`
+    EntityID   uint                   `json:"entity_id" gorm:"not null;index:idx_audit_entity"`
+    Changes    map[string]AuditChange `json:"changes" gorm:"serializer:json;type:text"`
+    Reason     string                 `json:"reason"`                       // header X-Audit-Reason
+    RequestID  string                 `json:"request_id" gorm:"index"`
+    IPAddress  string                 `json:"ip_address"`
+    UserAgent  string                 `json:"user_agent"`
+    CreatedAt  time.Time              `json:"created_at" gorm:"index"`
+}
+
+// AuditChange - nilai field sebelum dan sesudah perubahan (null untuk create / delete)
+type AuditChange struct {
+    From interface{} `json:"from"`
+    To   interface{} `json:"to"`
+}
+
+const (
+    AuditTaskCreate              = "task.create"
+    AuditTaskUpdate              = "task.update"
+    AuditTaskDelete              = "task.delete"
+    AuditTaskChecklistAdd        = "task.checklist_add"
+    AuditTaskChecklistUpdate     = "task.checklist_update"
+    AuditTaskChecklistDelete     = "task.checklist_delete"
+    AuditTaskChecklistReorder    = "task.checklist_reorder"
+    AuditTaskReminderUpdate      = "task.reminder_update"
+    AuditTaskReminderSnooze      = "task.reminder_snooze"
+    AuditTaskReminderAcknowledge = "task.reminder_acknowledge"
+    AuditUserCreate              = "user.create"
+    AuditUserUpdate              = "user.update"
+    AuditUserRoleChange          = "user.role_change"
+    AuditUserPasswordChange      = "user.password_change"
+    AuditUserSettingsUpdate      = "user.settings_update"
+    AuditCategoryCreate          = "category.create"
+    AuditCategoryUpdate          = "category.update"
+    AuditCategoryDelete          = "category.delete"
+)
@@ -10,9 +10,23 @@ type Category struct {
     Slug        string    `json:"slug" gorm:"unique;not null"`
     Color       string    `json:"color" gorm:"default:#3B82F6"`
     Description string    `json:"description"`
+    IsPrivate   bool      `json:"is_private" gorm:"default:false"` // private: hanya untuk user di user_categories
     CreatedAt   time.Time `json:"created_at"`
     UpdatedAt   time.Time `json:"updated_at"`
     
     Tasks       []Task    `json:"tasks,omitempty" gorm:"foreignKey:CategoryID"`
     Users       []User    `json:"users,omitempty" gorm:"many2many:user_categories;"`
-}
\ No newline at end of file
+}
+
+type CreateCategoryRequest struct {
+    Name        string `json:"name" binding:"required,max=100"`
+    Color       string `json:"color"`
+    Description string `json:"description"`
+    IsPrivate   *bool  `json:"is_private"` // hanya admin yang bisa membuat kategori global
+}
+
+type UpdateCategoryRequest struct {
+    Name        string `json:"name" binding:"max=100"`
+    Color       string `json:"color"`
+    Description string `json:"description"`
+}
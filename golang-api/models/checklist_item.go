@@ -0,0 +1,31 @@
+package models
+
+import (
+    "time"
+)
+
+// ChecklistItem - langkah-langkah kecil di dalam sebuah task
+type ChecklistItem struct {
+    ID          uint       `json:"id" gorm:"primaryKey"`
+    TaskID      uint       `json:"task_id" gorm:"not null;index"`
+    Title       string     `json:"title" gorm:"not null"`
+    Position    int        `json:"position" gorm:"not null;default:0"`
+    Completed   bool       `json:"completed" gorm:"default:false"`
+    CompletedAt *time.Time `json:"completed_at"`
+    CreatedAt   time.Time  `json:"created_at"`
+    UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type CreateChecklistItemRequest struct {
+    Title    string `json:"title" binding:"required"`
+    Position *int   `json:"position"`
+}
+
+type UpdateChecklistItemRequest struct {
+    Title     string `json:"title"`
+    Completed *bool  `json:"completed"`
+}
+
+type ReorderChecklistRequest struct {
+    ItemIDs []uint `json:"item_ids" binding:"required,min=1"`
+}
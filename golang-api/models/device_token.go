@@ -0,0 +1,28 @@
+package models
+
+import (
+    "time"
+)
+
+// DeviceToken - FCM registration token untuk satu device; satu user bisa punya banyak device
+type DeviceToken struct {
+    ID         uint      `json:"id" gorm:"primaryKey"`
+    UserID     uint      `json:"user_id" gorm:"not null;index"`
+    Token      string    `json:"-" gorm:"not null;uniqueIndex"`
+    Platform   string    `json:"platform" gorm:"not null;default:web"`
+    UserAgent  string    `json:"user_agent"`
+    LastSeenAt time.Time `json:"last_seen_at"`
+    CreatedAt  time.Time `json:"created_at"`
+    UpdatedAt  time.Time `json:"updated_at"`
+
+    User       User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type RegisterDeviceRequest struct {
+    Token    string `json:"token" binding:"required,max=4096"`
+    Platform string `json:"platform" binding:"required,oneof=android ios web"`
+}
+
+type UnregisterDeviceRequest struct {
+    Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+    "time"
+)
+
+// DigestLog - penanda digest yang sudah dikirim; unique (user_id, period) mencegah digest ganda setelah restart
+type DigestLog struct {
+    ID             uint      `json:"id" gorm:"primaryKey"`
+    UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_digest_user_period"`
+    Period         string    `json:"period" gorm:"not null;uniqueIndex:idx_digest_user_period"` // mis. "daily:2025-07-21"
+    DueCount       int       `json:"due_count"`
+    OverdueCount   int       `json:"overdue_count"`
+    CompletedCount int       `json:"completed_count"`
+    SentAt         time.Time `json:"sent_at"`
+}
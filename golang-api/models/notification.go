@@ -0,0 +1,73 @@
+package models
+
+import (
+    "time"
+)
+
+// Notification - notifikasi yang tersimpan di inbox in-app, terlepas dari channel pengirimannya
+type Notification struct {
+    ID        uint              `json:"id" gorm:"primaryKey"`
+    UserID    uint              `json:"user_id" gorm:"not null;index:idx_notifications_user_read"`
+    Type      string            `json:"type" gorm:"not null"`
+    Title     string            `json:"title" gorm:"not null"`
+    Body      string            `json:"body"`
+    Data      map[string]string `json:"data" gorm:"serializer:json;type:text"`
+    ReadAt    *time.Time        `json:"read_at" gorm:"index:idx_notifications_user_read"`
+    InboxKey  *string           `json:"-" gorm:"uniqueIndex"` // diisi untuk notifikasi yang bisa dikirim ulang
+    CreatedAt time.Time         `json:"created_at"`
+
+    User      User              `json:"-" gorm:"foreignKey:UserID"`
+}
+
+const (
+    NotificationTypeReminder     = "reminder"
+    NotificationTypeStatusUpdate = "status_update"
+    NotificationTypeDigest       = "digest"
+)
+
+const (
+    ChannelPush    = "push"
+    ChannelEmail   = "email"
+    ChannelWebhook = "webhook"
+)
+
+var NotificationTypes = []string{NotificationTypeReminder, NotificationTypeStatusUpdate, NotificationTypeDigest}
+
+var NotificationChannels = []string{ChannelPush, ChannelEmail, ChannelWebhook}
+
+// DefaultLocale dipakai jika locale user kosong atau belum didukung
+const DefaultLocale = "en"
+
+var SupportedLocales = []string{"en", "id"}
+
+// DefaultNotificationChannels dipakai untuk tipe notifikasi yang belum diatur user
+var DefaultNotificationChannels = map[string][]string{
+    NotificationTypeReminder:     {ChannelPush},
+    NotificationTypeStatusUpdate: {ChannelPush},
+    NotificationTypeDigest:       {ChannelEmail},
+}
+
+// ChannelsFor mengembalikan channel yang dipakai user untuk tipe notifikasi tertentu
+func (u User) ChannelsFor(notificationType string) []string {
+    if channels, ok := u.NotificationChannels[notificationType]; ok {
+        return channels
+    }
+    return DefaultNotificationChannels[notificationType]
+}
+
+func IsNotificationType(value string) bool {
+    return contains(NotificationTypes, value)
+}
+
+func IsNotificationChannel(value string) bool {
+    return contains(NotificationChannels, value)
+}
+
+func contains(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+    "encoding/json"
+    "time"
+)
+
+// OutboxEvent - domain event yang ditulis dalam transaksi yang sama dengan perubahan datanya,
+// lalu dipublikasikan oleh OutboxWorker ke subscriber in-process (at-least-once)
+type OutboxEvent struct {
+    ID            uint       `json:"id" gorm:"primaryKey"`
+    AggregateType string     `json:"aggregate_type" gorm:"not null;index:idx_outbox_aggregate"` // task / user / category
+    AggregateID   uint       `json:"aggregate_id" gorm:"not null;index:idx_outbox_aggregate"`
+    EventType     string     `json:"event_type" gorm:"not null"`                                  // mis. task.created
+    UserID        uint       `json:"user_id" gorm:"index"`                                        // pemilik data, 0 untuk data global
+    ActorID       uint       `json:"actor_id"`                                                    // user yang melakukan perubahan, 0 = sistem
+    Payload       string     `json:"payload" gorm:"type:text;not null"`
+    Attempts      int        `json:"attempts" gorm:"default:0"`
+    NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index"`
+    LastError     string     `json:"last_error"`
+    PublishedAt   *time.Time `json:"published_at" gorm:"index"`
+    CreatedAt     time.Time  `json:"created_at" gorm:"index"`                                   // dibaca OutboxService.Broadcast
+}
+
+// ProcessedEvent - penanda event yang sudah diproses consumer tertentu, supaya event yang
+// dipublikasikan ulang tidak diproses dua kali oleh consumer yang sama
+type ProcessedEvent struct {
+    Consumer    string    `json:"consumer" gorm:"primaryKey"`
+    EventID     uint      `json:"event_id" gorm:"primaryKey"`
+    ProcessedAt time.Time `json:"processed_at"`
+}
+
+const (
+    AggregateTask     = "task"
+    AggregateUser     = "user"
+    AggregateCategory = "category"
+)
+
+const (
+    EventTaskCreated       = "task.created"
+    EventTaskUpdated       = "task.updated"
+    EventTaskStatusChanged = "task.status_changed"
+    EventTaskDeleted       = "task.deleted"
+    EventReminderSent      = "reminder.sent"
+    EventUserCreated       = "user.created"
+    EventUserUpdated       = "user.updated"
+    EventCategoryCreated   = "category.created"
+    EventCategoryUpdated   = "category.updated"
+    EventCategoryDeleted   = "category.deleted"
+)
+
+// Decode membaca payload event ke struct / map tujuan
+func (e OutboxEvent) Decode(v interface{}) error {
+    return json.Unmarshal([]byte(e.Payload), v)
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+var weekdayCodes = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Location mengembalikan zona waktu user; zona yang tidak dikenal dianggap UTC
+func (u User) Location() *time.Location {
+    if u.Timezone == "" {
+        return time.UTC
+    }
+    loc, err := time.LoadLocation(u.Timezone)
+    if err != nil {
+        return time.UTC
+    }
+    return loc
+}
+
+// InQuietHours mengecek apakah t jatuh di jam tenang atau hari do-not-disturb user (waktu lokal user)
+func (u User) InQuietHours(t time.Time) bool {
+    return u.inQuietHours(t.In(u.Location()))
+}
+
+func (u User) inQuietHours(local time.Time) bool {
+    // Data lama dengan DND setiap hari diabaikan supaya notifikasi tidak ditunda selamanya
+    if !CoversEveryWeekday(u.DoNotDisturbDays) {
+        for _, day := range u.DoNotDisturbDays {
+            if strings.EqualFold(day, weekdayCodes[local.Weekday()]) {
+                return true
+            }
+        }
+    }
+
+    start, okStart := ParseClock(u.QuietHoursStart)
+    end, okEnd := ParseClock(u.QuietHoursEnd)
+    if !okStart || !okEnd || start == end {
+        return false
+    }
+
+    minute := local.Hour()*60 + local.Minute()
+    if start < end {
+        return minute >= start && minute < end
+    }
+    // Window melewati tengah malam, mis. 22:00 - 07:00
+    return minute >= start || minute < end
+}
+
+// NextNotificationTime mengembalikan waktu paling awal mulai dari t yang berada di luar jam tenang
+func (u User) NextNotificationTime(t time.Time) time.Time {
+    // Maju per menit cukup murah dan aman terhadap DST; minimal satu hari bebas DND, jadi 8 hari selalu cukup
+    next := t.In(u.Location())
+    for i := 0; i < 8*24*60 && u.inQuietHours(next); i++ {
+        next = next.Add(time.Minute).Truncate(time.Minute)
+    }
+    return next.In(t.Location())
+}
+
+// CoversEveryWeekday mengecek apakah daftar hari berisi ketujuh hari (duplikat dihitung sekali)
+func CoversEveryWeekday(days []string) bool {
+    seen := make(map[string]bool, len(days))
+    for _, day := range days {
+        seen[strings.ToLower(day)] = true
+    }
+    for _, code := range weekdayCodes {
+        if !seen[code] {
+            return false
+        }
+    }
+    return true
+}
+
+// ParseClock mem-parse "HH:MM" menjadi menit sejak tengah malam
+func ParseClock(value string) (int, bool) {
+    var hour, minute int
+    if len(value) != 5 {
+        return 0, false
+    }
+    if _, err := fmt.Sscanf(value, "%02d:%02d", &hour, &minute); err != nil {
+        return 0, false
+    }
+    if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+        return 0, false
+    }
+    return hour*60 + minute, true
+}
@@ -0,0 +1,11 @@
+package models
+
+import (
+    "time"
+)
+
+// SchemaMigration - penanda migrasi data yang hanya boleh dijalankan sekali
+type SchemaMigration struct {
+    Name      string    `json:"name" gorm:"primaryKey"`
+    AppliedAt time.Time `json:"applied_at"`
+}
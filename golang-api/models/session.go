@@ -0,0 +1,36 @@
+package models
+
+import (
+    "time"
+)
+
+// Session - token login native (email/password), disimpan sebagai hash SHA-256
+type Session struct {
+    ID         uint       `json:"id" gorm:"primaryKey"`
+    UserID     uint       `json:"user_id" gorm:"not null;index"`
+    TokenHash  string     `json:"-" gorm:"uniqueIndex;not null"`
+    UserAgent  string     `json:"user_agent"`
+    IPAddress  string     `json:"ip_address"`
+    ExpiresAt  time.Time  `json:"expires_at"`
+    LastUsedAt *time.Time `json:"last_used_at"`
+    RevokedAt  *time.Time `json:"revoked_at"`
+    CreatedAt  time.Time  `json:"created_at"`
+    
+    User       User       `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type RegisterRequest struct {
+    Name     string `json:"name" binding:"required"`
+    Email    string `json:"email" binding:"required,email"`
+    Password string `json:"password" binding:"required,min=8"`
+}
+
+type LoginRequest struct {
+    Email    string `json:"email" binding:"required,email"`
+    Password string `json:"password" binding:"required"`
+}
+
+type ChangePasswordRequest struct {
+    CurrentPassword string `json:"current_password"`
+    NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
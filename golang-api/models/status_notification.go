@@ -0,0 +1,16 @@
+package models
+
+import (
+    "time"
+)
+
+// PendingStatusNotification - notifikasi perubahan status yang menunggu jendela debounce atau jam tenang selesai.
+// Disimpan di database supaya tidak hilang saat restart; satu baris per task, status awal diambil dari perubahan pertama.
+type PendingStatusNotification struct {
+    ID         uint      `json:"id" gorm:"primaryKey"`
+    TaskID     uint      `json:"task_id" gorm:"not null;uniqueIndex"`
+    FromStatus string    `json:"from_status" gorm:"not null"`
+    SendAfter  time.Time `json:"send_after" gorm:"not null;index"`
+    CreatedAt  time.Time `json:"created_at"`
+    UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -6,38 +6,48 @@ import (
 )
 
 type Task struct {
-    ID             uint           `json:"id" gorm:"primaryKey"`
-    Title          string         `json:"title" gorm:"not null"`
-    Description    string         `json:"description"`
-    Status         string         `json:"status" gorm:"default:todo;check:status IN ('todo','in_progress','done')"`
-    Priority       string         `json:"priority" gorm:"default:medium;check:priority IN ('low','medium','high')"`
-    UserID         uint           `json:"user_id" gorm:"not null"`
-    CategoryID     uint           `json:"category_id" gorm:"not null"`
-    Deadline       *time.Time     `json:"deadline"`
-    ReminderSentAt *time.Time     `json:"reminder_sent_at"`
-    CreatedAt      time.Time      `json:"created_at"`
-    UpdatedAt      time.Time      `json:"updated_at"`
-    DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"index"`
-    
-    User           User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
-    Category       Category       `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+    ID               uint           `json:"id" gorm:"primaryKey"`
+    Title            string         `json:"title" gorm:"not null"`
+    Description      string         `json:"description"`
+    Status           string         `json:"status" gorm:"default:todo;check:status IN ('todo','in_progress','done')"`
+    Priority         string         `json:"priority" gorm:"default:medium;check:priority IN ('low','medium','high')"`
+    UserID           uint           `json:"user_id" gorm:"not null"`
+    CategoryID       uint           `json:"category_id" gorm:"not null"`
+    Deadline         *time.Time     `json:"deadline"`
+    ReminderSentAt   *time.Time     `json:"reminder_sent_at"`
+    Progress         int            `json:"progress" gorm:"default:0"` // persentase checklist yang selesai
+    RecurrenceRule   string         `json:"recurrence_rule"`           // RRULE, mis. FREQ=WEEKLY;BYDAY=MO
+    SeriesID         *uint          `json:"series_id" gorm:"index"`    // ID task pertama dalam series berulang
+    OccurrenceNumber int            `json:"occurrence_number" gorm:"default:0"`
+    CreatedAt        time.Time      `json:"created_at"`
+    UpdatedAt        time.Time      `json:"updated_at"`
+    DeletedAt        gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+    User           User            `json:"user,omitempty" gorm:"foreignKey:UserID"`
+    Category       Category        `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+    ChecklistItems []ChecklistItem `json:"checklist_items,omitempty" gorm:"foreignKey:TaskID"`
+    Reminders      []TaskReminder  `json:"reminders,omitempty" gorm:"foreignKey:TaskID"`
 }
 
 type CreateTaskRequest struct {
-    Title       string     `json:"title" binding:"required"`
-    Description string     `json:"description"`
-    Status      string     `json:"status"`
-    Priority    string     `json:"priority"`
-    UserID      uint       `json:"user_id" binding:"required"`
-    CategoryID  uint       `json:"category_id" binding:"required"`
-    Deadline    *time.Time `json:"deadline"`
+    Title           string     `json:"title" binding:"required"`
+    Description     string     `json:"description"`
+    Status          string     `json:"status"`
+    Priority        string     `json:"priority"`
+    UserID          uint       `json:"user_id"` // diabaikan pada /api/me/tasks
+    CategoryID      uint       `json:"category_id" binding:"required"`
+    Deadline        *time.Time `json:"deadline"`
+    RecurrenceRule  string     `json:"recurrence_rule"`
+    ReminderOffsets []int      `json:"reminder_offsets" binding:"max=10,dive,min=0,max=43200"` // kosong = default user
 }
 
 type UpdateTaskRequest struct {
-    Title       string     `json:"title"`
-    Description string     `json:"description"`
-    Status      string     `json:"status"`
-    Priority    string     `json:"priority"`
-    CategoryID  uint       `json:"category_id"`
-    Deadline    *time.Time `json:"deadline"`
-}
\ No newline at end of file
+    Title          string     `json:"title"`
+    Description    string     `json:"description"`
+    Status         string     `json:"status"`
+    Priority       string     `json:"priority"`
+    CategoryID     uint       `json:"category_id"`
+    Deadline       *time.Time `json:"deadline"`
+    RecurrenceRule *string    `json:"recurrence_rule"` // string kosong menghentikan pengulangan
+    ReminderOffsets []int     `json:"reminder_offsets" binding:"max=10,dive,min=0,max=43200"` // nil = tidak diubah
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+    "time"
+)
+
+// TaskChange - satu field task yang diubah lewat UpdateTask, dasar timeline /api/tasks/:id/history
+type TaskChange struct {
+    ID        uint        `json:"id" gorm:"primaryKey"`
+    TaskID    uint        `json:"task_id" gorm:"not null;index"`
+    ActorID   uint        `json:"actor_id"` // 0 = sistem
+    Field     string      `json:"field" gorm:"not null"`
+    OldValue  interface{} `json:"old_value" gorm:"serializer:json;type:text"`
+    NewValue  interface{} `json:"new_value" gorm:"serializer:json;type:text"`
+    CreatedAt time.Time   `json:"created_at" gorm:"index"`
+}
+
+// TaskHistoryFields - field task yang dicatat di riwayat; field internal seperti progress tidak ikut
+var TaskHistoryFields = []string{
+    "title",
+    "description",
+    "status",
+    "priority",
+    "category_id",
+    "deadline",
+    "recurrence_rule",
+}
+
+// Tipe entri timeline task
+const (
+    TaskHistoryCreated       = "created"
+    TaskHistoryFieldChanged  = "field_changed"
+    TaskHistoryStatusChanged = "status_changed"
+    TaskHistoryReminderSent  = "reminder_sent"
+)
+
+// TaskHistoryEntry - satu entri timeline; field yang terisi tergantung Type
+type TaskHistoryEntry struct {
+    Type          string      `json:"type"`
+    At            time.Time   `json:"at"`
+    ActorID       uint        `json:"actor_id"`
+    ActorName     string      `json:"actor_name,omitempty"`
+    Field         string      `json:"field,omitempty"`
+    From          interface{} `json:"from"`
+    To            interface{} `json:"to"`
+    ReminderID    uint        `json:"reminder_id,omitempty"`
+    OffsetMinutes *int        `json:"offset_minutes,omitempty"`
+}
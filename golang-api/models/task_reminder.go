@@ -0,0 +1,67 @@
+package models
+
+import (
+    "time"
+)
+
+// DefaultReminderOffsets dipakai jika user belum mengatur default reminder sendiri (menit sebelum deadline)
+var DefaultReminderOffsets = []int{5}
+
+// DefaultSnoozeMinutes dipakai jika snooze dikirim tanpa minutes maupun until
+const DefaultSnoozeMinutes = 10
+
+// MaxSnoozeMinutes - batas snooze (7 hari), berlaku untuk minutes maupun until
+const MaxSnoozeMinutes = 7 * 24 * 60
+
+// Action identifier di payload FCM, dipakai client untuk menampilkan tombol aksi
+const (
+    ReminderActionSnooze      = "snooze_reminder"
+    ReminderActionAcknowledge = "acknowledge_reminder"
+    ReminderActionCategory    = "TASK_REMINDER"
+)
+
+// TaskReminder - satu jadwal reminder untuk task, dikirim OffsetMinutes sebelum deadline
+type TaskReminder struct {
+    ID             uint       `json:"id" gorm:"primaryKey"`
+    TaskID         uint       `json:"task_id" gorm:"not null;uniqueIndex:idx_task_reminder_offset"`
+    OffsetMinutes  int        `json:"offset_minutes" gorm:"not null;uniqueIndex:idx_task_reminder_offset"`
+    RemindAt       time.Time  `json:"remind_at" gorm:"not null;index"`
+    SentAt         *time.Time `json:"sent_at"`
+    SnoozedUntil   *time.Time `json:"snoozed_until" gorm:"index"`
+    SnoozeCount    int        `json:"snooze_count" gorm:"default:0"`
+    AcknowledgedAt *time.Time `json:"acknowledged_at"`
+    FailedAttempts int        `json:"failed_attempts" gorm:"default:0"` // pengiriman gagal berturut-turut
+    RetryAt        *time.Time `json:"retry_at"`                         // percobaan berikutnya setelah gagal
+    CreatedAt      time.Time  `json:"created_at"`
+    UpdatedAt      time.Time  `json:"updated_at"`
+    
+    Task           Task       `json:"-" gorm:"foreignKey:TaskID"`
+}
+
+// TaskReminderSend - catatan append-only setiap reminder yang terkirim, dasar entri reminder_sent di
+// riwayat task. sent_at di TaskReminder bisa berubah (snooze, reschedule), catatan ini tidak.
+type TaskReminderSend struct {
+    ID            uint      `json:"id" gorm:"primaryKey"`
+    TaskID        uint      `json:"task_id" gorm:"not null;index"`
+    ReminderID    uint      `json:"reminder_id" gorm:"not null"`
+    OffsetMinutes int       `json:"offset_minutes"`
+    SentAt        time.Time `json:"sent_at" gorm:"not null"`
+}
+
+// DueAt - waktu reminder akan dikirim, memperhitungkan snooze
+func (r TaskReminder) DueAt() time.Time {
+    if r.SnoozedUntil != nil {
+        return *r.SnoozedUntil
+    }
+    return r.RemindAt
+}
+
+type UpdateRemindersRequest struct {
+    Offsets []int `json:"offsets" binding:"max=10,dive,min=0,max=43200"`
+}
+
+// SnoozeReminderRequest - isi salah satu: minutes atau until
+type SnoozeReminderRequest struct {
+    Minutes int        `json:"minutes" binding:"omitempty,min=1,max=10080"`
+    Until   *time.Time `json:"until"`
+}
@@ -6,28 +6,109 @@ import (
 )
 
 type User struct {
-    ID          uint           `json:"id" gorm:"primaryKey"`
-    Name        string         `json:"name" gorm:"not null"`
-    Email       string         `json:"email" gorm:"unique;not null"`
-    Password    string         `json:"-" gorm:"default:''"`  // TAMBAHAN: default empty, hide dari JSON
-    FirebaseUID string         `json:"firebase_uid"`
-    FCMToken    string         `json:"fcm_token"`
-    CreatedAt   time.Time      `json:"created_at"`
-    UpdatedAt   time.Time      `json:"updated_at"`
-    DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
-    
-    Tasks       []Task         `json:"tasks,omitempty" gorm:"foreignKey:UserID"`
-    Categories  []Category     `json:"categories,omitempty" gorm:"many2many:user_categories;"`
+    ID                     uint                `json:"id" gorm:"primaryKey"`
+    Name                   string              `json:"name" gorm:"not null"`
+    Email                  string              `json:"email" gorm:"unique;not null"`
+    Password               string              `json:"-" gorm:"default:''"`                                    // TAMBAHAN: default empty, hide dari JSON
+    FirebaseUID            string              `json:"firebase_uid"`
+    Role                   string              `json:"role" gorm:"default:member;check:role IN ('admin','member','auditor')"`
+    ReminderOffsets        []int               `json:"reminder_offsets" gorm:"serializer:json;type:text"`      // default reminder (menit sebelum deadline)
+    Timezone               string              `json:"timezone" gorm:"default:UTC"`                            // nama IANA, mis. Asia/Jakarta
+    Locale                 string              `json:"locale" gorm:"default:en"`                               // bahasa notifikasi: en / id
+    QuietHoursStart        string              `json:"quiet_hours_start"`                                      // "HH:MM" waktu lokal, kosong = nonaktif
+    QuietHoursEnd          string              `json:"quiet_hours_end"`
+    DoNotDisturbDays       []string            `json:"do_not_disturb_days" gorm:"serializer:json;type:text"`   // mis. ["sat", "sun"]
+    StatusNotifications    []string            `json:"status_notifications" gorm:"serializer:json;type:text"`  // status yang memicu notifikasi, nil = default
+    NotificationChannels   map[string][]string `json:"notification_channels" gorm:"serializer:json;type:text"` // tipe notifikasi -> channel
+    NotificationWebhookURL string              `json:"notification_webhook_url"`
+    DigestFrequency        string              `json:"digest_frequency" gorm:"default:off;check:digest_frequency IN ('off','daily','weekly')"`
+    DigestTime             string              `json:"digest_time" gorm:"default:07:00"`                       // "HH:MM" waktu lokal user
+    DigestWeekday          string              `json:"digest_weekday" gorm:"default:mon"`                      // hari pengiriman digest mingguan
+    CreatedAt              time.Time           `json:"created_at"`
+    UpdatedAt              time.Time           `json:"updated_at"`
+    DeletedAt              gorm.DeletedAt      `json:"deleted_at" gorm:"index"`
+
+    Tasks      []Task     `json:"tasks,omitempty" gorm:"foreignKey:UserID"`
+    Categories []Category `json:"categories,omitempty" gorm:"many2many:user_categories;"`
 }
 
 type CreateUserRequest struct {
-    Name        string `json:"name" binding:"required"`
-    Email       string `json:"email" binding:"required,email"`
-    Password    string `json:"-"` 
-    FirebaseUID string `json:"firebase_uid"`
-    FCMToken    string `json:"fcm_token"`
+    Name     string `json:"name" binding:"required"`
+    Email    string `json:"email" binding:"required,email"`
+    Password string `json:"password" binding:"omitempty,min=8"`
+    FCMToken string `json:"fcm_token"` // didaftarkan sebagai device web
+}
+
+type UpdateNotificationSettingsRequest struct {
+    Timezone            *string             `json:"timezone"`
+    Locale              *string             `json:"locale" binding:"omitempty,oneof=en id"`
+    QuietHoursStart     *string             `json:"quiet_hours_start"`
+    QuietHoursEnd       *string             `json:"quiet_hours_end"`
+    DoNotDisturbDays    *[]string           `json:"do_not_disturb_days" binding:"omitempty,max=7,dive,oneof=sun mon tue wed thu fri sat"`
+    StatusNotifications *[]string           `json:"status_notifications" binding:"omitempty,max=3,dive,oneof=todo in_progress done"`
+    Channels            map[string][]string `json:"channels"` // hanya tipe yang dikirim yang diubah
+    WebhookURL          *string             `json:"webhook_url"`
+    DigestFrequency     *string             `json:"digest_frequency" binding:"omitempty,oneof=off daily weekly"`
+    DigestTime          *string             `json:"digest_time"`
+    DigestWeekday       *string             `json:"digest_weekday" binding:"omitempty,oneof=sun mon tue wed thu fri sat"`
+}
+
+type UpdateRoleRequest struct {
+    Role string `json:"role" binding:"required,oneof=admin member auditor"`
 }
 
-type UpdateFCMTokenRequest struct {
-    FCMToken string `json:"fcm_token" binding:"required"`
-}
\ No newline at end of file
+const (
+    RoleAdmin   = "admin"
+    RoleMember  = "member"
+    RoleAuditor = "auditor" // read-only akses ke seluruh data
+)
+
+const (
+    PermissionReadAllData      = "read_all_data"
+    PermissionWriteAllData     = "write_all_data"
+    PermissionManageUsers      = "manage_users"
+    PermissionManageCategories = "manage_categories"
+    PermissionViewAuditLog     = "view_audit_log"
+)
+
+var rolePermissions = map[string][]string{
+    RoleAdmin: {
+        PermissionReadAllData,
+        PermissionWriteAllData,
+        PermissionManageUsers,
+        PermissionManageCategories,
+        PermissionViewAuditLog,
+    },
+    RoleAuditor: {
+        PermissionReadAllData,
+        PermissionViewAuditLog,
+    },
+    RoleMember: {},
+}
+
+// DefaultStatusNotifications - status yang memicu notifikasi jika user belum mengaturnya sendiri
+var DefaultStatusNotifications = []string{"in_progress", "done"}
+
+// WantsStatusNotification mengecek apakah user ingin diberi tahu saat task berpindah ke status tertentu
+func (u User) WantsStatusNotification(status string) bool {
+    statuses := u.StatusNotifications
+    if statuses == nil {
+        statuses = DefaultStatusNotifications
+    }
+    for _, s := range statuses {
+        if s == status {
+            return true
+        }
+    }
+    return false
+}
+
+// Can mengecek apakah role user memiliki permission tertentu
+func (u User) Can(permission string) bool {
+    for _, p := range rolePermissions[u.Role] {
+        if p == permission {
+            return true
+        }
+    }
+    return false
+}
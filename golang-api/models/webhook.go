@@ -0,0 +1,69 @@
+package models
+
+import (
+    "time"
+)
+
+// WebhookSubscription - endpoint milik user yang menerima event task (payload JSON bertanda tangan HMAC)
+type WebhookSubscription struct {
+    ID          uint      `json:"id" gorm:"primaryKey"`
+    UserID      uint      `json:"user_id" gorm:"not null;index"`
+    URL         string    `json:"url" gorm:"not null"`
+    Secret      string    `json:"-" gorm:"not null"`                      // kunci HMAC-SHA256, hanya ditampilkan saat dibuat
+    Events      []string  `json:"events" gorm:"serializer:json;type:text"` // mis. ["task.created", "task.deleted"]
+    Description string    `json:"description"`
+    Active      bool      `json:"active" gorm:"default:true"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery - satu event untuk satu subscription; sekaligus antrian pengiriman dan log-nya
+type WebhookDelivery struct {
+    ID             uint       `json:"id" gorm:"primaryKey"`
+    SubscriptionID uint       `json:"subscription_id" gorm:"not null;index"`
+    EventID        string     `json:"event_id" gorm:"not null;index"` // sama untuk semua subscription dan redelivery event yang sama
+    Event          string     `json:"event" gorm:"not null"`
+    Payload        string     `json:"payload" gorm:"type:text;not null"`
+    Status         string     `json:"status" gorm:"default:pending;index;check:status IN ('pending','succeeded','failed')"`
+    Attempts       int        `json:"attempts" gorm:"default:0"`
+    NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"index"`
+    LastAttemptAt  *time.Time `json:"last_attempt_at"`
+    ResponseStatus int        `json:"response_status"`
+    LastError      string     `json:"last_error"`
+    DeliveredAt    *time.Time `json:"delivered_at"`
+    RedeliveryOf   *uint      `json:"redelivery_of"`
+    CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookEvents - event outbox yang bisa dilanggan lewat webhook
+var WebhookEvents = []string{
+    EventTaskCreated,
+    EventTaskUpdated,
+    EventTaskStatusChanged,
+    EventTaskDeleted,
+    EventReminderSent,
+}
+
+const (
+    WebhookDeliveryPending   = "pending"
+    WebhookDeliverySucceeded = "succeeded"
+    WebhookDeliveryFailed    = "failed"
+)
+
+func (s WebhookSubscription) Subscribes(event string) bool {
+    return contains(s.Events, event)
+}
+
+type CreateWebhookRequest struct {
+    URL         string   `json:"url" binding:"required"`
+    Events      []string `json:"events" binding:"required,min=1,dive,oneof=task.created task.updated task.status_changed task.deleted reminder.sent"`
+    Secret      string   `json:"secret" binding:"omitempty,min=16"` // kosong = dibuat otomatis
+    Description string   `json:"description"`
+}
+
+type UpdateWebhookRequest struct {
+    URL         *string   `json:"url"`
+    Events      *[]string `json:"events" binding:"omitempty,min=1,dive,oneof=task.created task.updated task.status_changed task.deleted reminder.sent"`
+    Description *string   `json:"description"`
+    Active      *bool     `json:"active"`
+}
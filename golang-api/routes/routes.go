@@ -1,18 +1,26 @@
 package routes
 
 import (
+    "taskflow-api/config"
     "taskflow-api/controllers"
     "taskflow-api/middleware"
+    "taskflow-api/models"
 
     "github.com/gin-gonic/gin"
 )
 
 func SetupRoutes() *gin.Engine {
+    return SetupRoutesWithVerifier(middleware.NewFirebaseTokenVerifier(config.FirebaseAuth))
+}
+
+// SetupRoutesWithVerifier - sama dengan SetupRoutes tapi token verifier bisa diganti (mis. key set lokal)
+func SetupRoutesWithVerifier(verifier middleware.TokenVerifier) *gin.Engine {
     gin.SetMode(gin.ReleaseMode)
 
     r := gin.New()
 
     // Middlewares
+    r.Use(middleware.RequestID())
     r.Use(middleware.Logger())
     r.Use(middleware.ErrorHandler())
     r.Use(middleware.CORSMiddleware())
@@ -30,33 +38,120 @@ func SetupRoutes() *gin.Engine {
     // Grouped API routes
     api := r.Group("/api")
     {
+        // Registration only needs a verified token, the user row does not exist yet
+        api.POST("/users", middleware.VerifyToken(verifier), controllers.CreateUser)
+
+        // Native email/password auth
+        api.POST("/auth/register", controllers.Register)
+        api.POST("/auth/login", controllers.Login)
+    }
+
+    protected := api.Group("")
+    protected.Use(middleware.AuthRequired(verifier))
+
+    // Scopes only restrict requests authenticated with a personal API key
+    readTasks := middleware.RequireScope(models.ScopeTasksRead)
+
+    // Live event stream (SSE) and board WebSocket; browsers cannot set headers on these, so ?access_token= is accepted here
+    // (session token or Firebase ID token only, never an API key)
+    api.GET("/me/events", middleware.TokenFromQuery("access_token"), middleware.AuthRequired(verifier), readTasks, controllers.StreamMyEvents)
+    api.GET("/boards/ws", middleware.TokenFromQuery("access_token"), middleware.AuthRequired(verifier), readTasks, controllers.BoardSocket)
+
+    writeTasks := middleware.RequireScope(models.ScopeTasksWrite)
+    exportTasks := middleware.RequireScope(models.ScopeExport)
+    interactive := middleware.RequireInteractiveAuth()
+    {
+        // Session management
+        protected.POST("/auth/logout", interactive, controllers.Logout)
+        protected.POST("/auth/logout-all", interactive, controllers.LogoutAll)
+        protected.PUT("/auth/password", interactive, controllers.ChangePassword)
+
+        // Personal API keys
+        protected.GET("/me/api-keys", interactive, controllers.GetMyAPIKeys)
+        protected.POST("/me/api-keys", interactive, controllers.CreateMyAPIKey)
+        protected.DELETE("/me/api-keys/:id", interactive, controllers.RevokeMyAPIKey)
+
+        // Current user routes
+        protected.GET("/me", readTasks, controllers.GetMe)
+        protected.GET("/me/tasks", readTasks, controllers.GetMyTasks)
+        protected.POST("/me/tasks", writeTasks, controllers.CreateMyTask)
+        protected.GET("/me/tasks/:id", readTasks, controllers.GetMyTask)
+        protected.PUT("/me/tasks/:id", writeTasks, controllers.UpdateMyTask)
+        protected.DELETE("/me/tasks/:id", writeTasks, controllers.DeleteMyTask)
+        protected.GET("/me/export", exportTasks, controllers.ExportMyTasks)
+        protected.GET("/me/reminder-defaults", readTasks, controllers.GetMyReminderDefaults)
+        protected.PUT("/me/reminder-defaults", writeTasks, controllers.UpdateMyReminderDefaults)
+        protected.GET("/me/notification-settings", readTasks, controllers.GetMyNotificationSettings)
+        protected.PUT("/me/notification-settings", interactive, controllers.UpdateMyNotificationSettings)
+        protected.GET("/me/devices", readTasks, controllers.GetMyDevices)
+        protected.POST("/me/devices", writeTasks, controllers.RegisterMyDevice)
+        protected.POST("/me/devices/unregister", writeTasks, controllers.UnregisterMyDevice)
+        protected.DELETE("/me/devices/:id", writeTasks, controllers.DeleteMyDevice)
+        protected.GET("/me/notifications", readTasks, controllers.GetMyNotifications)
+        protected.GET("/me/notifications/unread-count", readTasks, controllers.GetMyUnreadNotificationCount)
+        protected.POST("/me/notifications/read-all", writeTasks, controllers.MarkAllNotificationsRead)
+        protected.POST("/me/notifications/:id/read", writeTasks, controllers.MarkNotificationRead)
+
+        // Outbound webhooks
+        protected.GET("/me/webhooks", readTasks, controllers.GetMyWebhooks)
+        protected.POST("/me/webhooks", interactive, controllers.CreateMyWebhook)
+        protected.GET("/me/webhooks/:id", readTasks, controllers.GetMyWebhook)
+        protected.PUT("/me/webhooks/:id", interactive, controllers.UpdateMyWebhook)
+        protected.DELETE("/me/webhooks/:id", interactive, controllers.DeleteMyWebhook)
+        protected.GET("/me/webhooks/:id/deliveries", readTasks, controllers.GetMyWebhookDeliveries)
+        protected.POST("/me/webhooks/:id/deliveries/:delivery_id/redeliver", interactive, controllers.RedeliverMyWebhook)
+
         // Task routes
-        api.GET("/users/:id/tasks", controllers.GetUserTasks)
-        api.POST("/tasks", controllers.CreateTask)
-        api.PUT("/tasks/:id", controllers.UpdateTask)
-        api.DELETE("/tasks/:id", controllers.DeleteTask)
+        protected.GET("/users/:id/tasks", readTasks, controllers.GetUserTasks)
+        protected.GET("/tasks/search", readTasks, controllers.SearchTasks)
+        protected.POST("/tasks", writeTasks, controllers.CreateTask)
+        protected.PUT("/tasks/:id", writeTasks, controllers.UpdateTask)
+        protected.DELETE("/tasks/:id", writeTasks, controllers.DeleteTask)
+        protected.GET("/tasks/:id/history", readTasks, controllers.GetTaskHistory)
+
+        // Checklist routes
+        protected.GET("/tasks/:id/checklist", readTasks, controllers.GetChecklist)
+        protected.POST("/tasks/:id/checklist", writeTasks, controllers.AddChecklistItem)
+        protected.PUT("/tasks/:id/checklist", writeTasks, controllers.ReorderChecklist)
+        protected.PUT("/tasks/:id/checklist/:item_id", writeTasks, controllers.UpdateChecklistItem)
+        protected.POST("/tasks/:id/checklist/:item_id/toggle", writeTasks, controllers.ToggleChecklistItem)
+        protected.DELETE("/tasks/:id/checklist/:item_id", writeTasks, controllers.DeleteChecklistItem)
+        
+        // Reminder routes
+        protected.GET("/tasks/:id/reminders", readTasks, controllers.GetTaskReminders)
+        protected.PUT("/tasks/:id/reminders", writeTasks, controllers.UpdateTaskReminders)
+        protected.POST("/tasks/:id/reminders/:reminder_id/snooze", writeTasks, controllers.SnoozeReminder)
+        protected.POST("/tasks/:id/reminders/:reminder_id/acknowledge", writeTasks, controllers.AcknowledgeReminder)
 
         // Category routes
-        api.GET("/categories", controllers.GetCategories)
-        api.GET("/categories/:id", controllers.GetCategoryById)
+        protected.GET("/categories", readTasks, controllers.GetCategories)
+        protected.GET("/categories/:id", readTasks, controllers.GetCategoryById)
+        protected.POST("/categories", writeTasks, controllers.CreateCategory)
+        protected.PUT("/categories/:id", writeTasks, controllers.UpdateCategory)
+        protected.DELETE("/categories/:id", writeTasks, controllers.DeleteCategory)
 
         // Export routes 
-        api.GET("/user-tasks/:user_id/export/csv", controllers.ExportUserTasks)
-        api.GET("/user-tasks/:user_id/export/json", controllers.ExportUserTasksJSON)
+        protected.GET("/user-tasks/:user_id/export/csv", exportTasks, controllers.ExportUserTasks)
+        protected.GET("/user-tasks/:user_id/export/json", exportTasks, controllers.ExportUserTasksJSON)
 
         // User routes
-        api.POST("/users", controllers.CreateUser)
-        api.GET("/users/:id", controllers.GetUserById)
-        api.GET("/users/firebase/:firebase_uid", controllers.GetUserByFirebaseUID)
-        api.PUT("/users/:id/fcm-token", controllers.UpdateFCMToken)
-        api.PUT("/users/:id", controllers.UpdateProfile)
+        protected.GET("/users", middleware.RequirePermission(models.PermissionReadAllData), controllers.GetUsers)
+        protected.PUT("/users/:id/role", interactive, middleware.RequirePermission(models.PermissionManageUsers), controllers.UpdateUserRole)
+        protected.GET("/users/:id", readTasks, controllers.GetUserById)
+        protected.GET("/users/firebase/:firebase_uid", readTasks, controllers.GetUserByFirebaseUID)
+        protected.PUT("/users/:id", writeTasks, controllers.UpdateProfile)
+
+        // Audit log (admin and auditor)
+        viewAuditLog := middleware.RequirePermission(models.PermissionViewAuditLog)
+        protected.GET("/audit-logs", viewAuditLog, controllers.GetAuditLogs)
+        protected.GET("/audit-logs/:id", viewAuditLog, controllers.GetAuditLog)
 
-        // Dashboard
-        api.GET("/dashboard/stats", controllers.GetDashboardStats)
+        // Dashboard (scoped to the caller unless they can read all data)
+        protected.GET("/dashboard/stats", readTasks, controllers.GetDashboardStats)
 
         // Weather
-        api.GET("/weather", controllers.GetWeatherData)
-        api.GET("/weather/multiple", controllers.GetMultipleCitiesWeather)
+        protected.GET("/weather", controllers.GetWeatherData)
+        protected.GET("/weather/multiple", controllers.GetMultipleCitiesWeather)
     }
 
     return r
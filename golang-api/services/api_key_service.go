@@ -0,0 +1,75 @@
+package services
+
+import (
+    "crypto/rand"
+    "encoding/base64"
+    "errors"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+)
+
+// APIKeyPrefix membedakan personal API key dari session token dan Firebase ID token
+const APIKeyPrefix = "tf_key_"
+
+var ErrInvalidAPIKey = errors.New("api key is invalid, expired or revoked")
+
+type APIKeyService struct{}
+
+func NewAPIKeyService() *APIKeyService {
+    return &APIKeyService{}
+}
+
+// CreateKey membuat API key baru dan mengembalikan key plaintext (hanya sekali)
+func (ks *APIKeyService) CreateKey(user models.User, req models.CreateAPIKeyRequest) (string, models.APIKey, error) {
+    raw := make([]byte, 32)
+    if _, err := rand.Read(raw); err != nil {
+        return "", models.APIKey{}, err
+    }
+    key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+    apiKey := models.APIKey{
+        UserID:    user.ID,
+        Name:      req.Name,
+        Prefix:    key[:len(APIKeyPrefix)+6],
+        KeyHash:   hashToken(key),
+        Scopes:    req.Scopes,
+        ExpiresAt: req.ExpiresAt,
+    }
+    if err := config.DB.Create(&apiKey).Error; err != nil {
+        return "", models.APIKey{}, err
+    }
+    return key, apiKey, nil
+}
+
+// ResolveKey memvalidasi key, mencatat last_used_at dan mengembalikan key beserta user pemiliknya
+func (ks *APIKeyService) ResolveKey(key string) (models.APIKey, error) {
+    var apiKey models.APIKey
+    err := config.DB.Preload("User").
+        Where("key_hash = ?", hashToken(key)).
+        Where("revoked_at IS NULL").
+        Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+        First(&apiKey).Error
+    if err != nil || apiKey.User.ID == 0 {
+        return apiKey, ErrInvalidAPIKey
+    }
+
+    now := time.Now()
+    config.DB.Model(&apiKey).UpdateColumn("last_used_at", now)
+    apiKey.LastUsedAt = &now
+    return apiKey, nil
+}
+
+func (ks *APIKeyService) ListKeys(userID uint) ([]models.APIKey, error) {
+    var keys []models.APIKey
+    err := config.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+    return keys, err
+}
+
+// RevokeKey mencabut key milik user; mengembalikan false jika key tidak ditemukan
+func (ks *APIKeyService) RevokeKey(userID uint, keyID uint) (bool, error) {
+    result := config.DB.Model(&models.APIKey{}).
+        Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+        Update("revoked_at", time.Now())
+    return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+    "encoding/json"
+    "reflect"
+    "strings"
+    "taskflow-api/models"
+    "unicode/utf8"
+
+    "gorm.io/gorm"
+)
+
+// maxAuditReasonLength - alasan dari header X-Audit-Reason dipotong supaya tidak membengkakkan tabel
+const maxAuditReasonLength = 500
+
+// auditIgnoredFields - field yang berubah otomatis atau berisi relasi, tidak dicatat di diff
+var auditIgnoredFields = map[string]bool{
+    "updated_at":      true,
+    "deleted_at":      true,
+    "user":            true,
+    "users":           true,
+    "category":        true,
+    "categories":      true,
+    "tasks":           true,
+    "checklist_items": true,
+    "reminders":       true,
+}
+
+// AuditContext - siapa dan dari mana perubahan dilakukan. Nilai kosong berarti perubahan oleh sistem (mis. worker).
+type AuditContext struct {
+    ActorID    uint
+    ActorEmail string
+    RequestID  string
+    IPAddress  string
+    UserAgent  string
+    Reason     string
+}
+
+// RecordAudit menulis audit log berisi diff field antara before dan after memakai transaksi pemanggil,
+// jadi audit log hanya ada jika perubahannya commit. before nil = create, after nil = delete.
+// Perubahan tanpa field yang berbeda tidak dicatat.
+func RecordAudit(tx *gorm.DB, actx AuditContext, action, entityType string, entityID uint, before, after interface{}) error {
+    changes, err := AuditDiff(before, after)
+    if err != nil {
+        return err
+    }
+    if len(changes) == 0 {
+        return nil
+    }
+    return RecordAuditChanges(tx, actx, action, entityType, entityID, changes)
+}
+
+// RecordAuditChanges - sama dengan RecordAudit tapi daftar perubahan disusun pemanggil
+// (mis. perubahan password yang nilainya tidak boleh disimpan)
+func RecordAuditChanges(tx *gorm.DB, actx AuditContext, action, entityType string, entityID uint, changes map[string]models.AuditChange) error {
+    reason := truncateReason(actx.Reason)
+    return tx.Create(&models.AuditLog{
+        ActorID:    actx.ActorID,
+        ActorEmail: actx.ActorEmail,
+        Action:     action,
+        EntityType: entityType,
+        EntityID:   entityID,
+        Changes:    changes,
+        Reason:     reason,
+        RequestID:  actx.RequestID,
+        IPAddress:  actx.IPAddress,
+        UserAgent:  actx.UserAgent,
+    }).Error
+}
+
+// truncateReason memotong alasan di batas karakter. Header bisa berisi UTF-8 tidak valid atau terpotong di tengah
+// karakter multi-byte, dan Postgres menolak keduanya, yang ikut menggagalkan transaksi pemanggil.
+func truncateReason(reason string) string {
+    reason = strings.ToValidUTF8(reason, "")
+    if len(reason) <= maxAuditReasonLength {
+        return reason
+    }
+    end := maxAuditReasonLength
+    for end > 0 && !utf8.RuneStart(reason[end]) {
+        end--
+    }
+    return reason[:end]
+}
+
+// AuditDiff membandingkan representasi JSON before dan after per field. Field dengan json:"-"
+// (mis. password) otomatis tidak ikut tercatat.
+func AuditDiff(before, after interface{}) (map[string]models.AuditChange, error) {
+    from, err := auditFields(before)
+    if err != nil {
+        return nil, err
+    }
+    to, err := auditFields(after)
+    if err != nil {
+        return nil, err
+    }
+
+    changes := make(map[string]models.AuditChange)
+    for field, value := range from {
+        if !reflect.DeepEqual(value, to[field]) {
+            changes[field] = models.AuditChange{From: value, To: to[field]}
+        }
+    }
+    for field, value := range to {
+        if _, exists := from[field]; !exists && value != nil {
+            changes[field] = models.AuditChange{From: nil, To: value}
+        }
+    }
+    return changes, nil
+}
+
+func auditFields(value interface{}) (map[string]interface{}, error) {
+    if value == nil {
+        return nil, nil
+    }
+    body, err := json.Marshal(value)
+    if err != nil {
+        return nil, err
+    }
+    var fields map[string]interface{}
+    if err := json.Unmarshal(body, &fields); err != nil {
+        return nil, err
+    }
+    for field := range auditIgnoredFields {
+        delete(fields, field)
+    }
+    return fields, nil
+}
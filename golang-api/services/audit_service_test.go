@@ -0,0 +1,32 @@
+package services
+
+import (
+    "strings"
+    "testing"
+    "unicode/utf8"
+)
+
+func TestTruncateReason(t *testing.T) {
+    tests := []struct {
+        name    string
+        reason  string
+        wantLen int
+    }{
+        {"short", "koreksi data", len("koreksi data")},
+        {"ascii over limit", strings.Repeat("a", maxAuditReasonLength+10), maxAuditReasonLength},
+        // "é" dua byte: batas 500 byte jatuh di tengah karakter ke-250 jika diawali satu byte
+        {"multi-byte at limit", "a" + strings.Repeat("é", maxAuditReasonLength), maxAuditReasonLength - 1},
+        {"invalid utf-8", "alasan\xff", len("alasan")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := truncateReason(tt.reason)
+            if !utf8.ValidString(got) {
+                t.Errorf("truncateReason() = %q, not valid UTF-8", got)
+            }
+            if len(got) != tt.wantLen {
+                t.Errorf("len(truncateReason()) = %d, want %d", len(got), tt.wantLen)
+            }
+        })
+    }
+}
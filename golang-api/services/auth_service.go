@@ -0,0 +1,119 @@
+package services
+
+import (
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "errors"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "golang.org/x/crypto/bcrypt"
+    "gorm.io/gorm"
+)
+
+// SessionTokenPrefix membedakan session token native dari Firebase ID token (JWT)
+const SessionTokenPrefix = "tf_sess_"
+
+const SessionTTL = 30 * 24 * time.Hour
+
+var ErrInvalidCredentials = errors.New("invalid email or password")
+var ErrInvalidSession = errors.New("session is invalid, expired or revoked")
+
+// dummyPasswordHash dipakai saat email tidak ditemukan supaya waktu respon login tetap sama
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("taskflow-dummy-password"), bcrypt.DefaultCost)
+
+type AuthService struct{}
+
+func NewAuthService() *AuthService {
+    return &AuthService{}
+}
+
+func (as *AuthService) HashPassword(password string) (string, error) {
+    hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+    if err != nil {
+        return "", err
+    }
+    return string(hash), nil
+}
+
+func (as *AuthService) CheckPassword(hash, password string) bool {
+    if hash == "" {
+        return false
+    }
+    return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Authenticate mencari user berdasarkan email lalu mencocokkan password
+func (as *AuthService) Authenticate(email, password string) (models.User, error) {
+    var user models.User
+    if err := config.DB.Where("LOWER(email) = ?", strings.ToLower(email)).First(&user).Error; err != nil {
+        bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+        return user, ErrInvalidCredentials
+    }
+
+    if !as.CheckPassword(user.Password, password) {
+        return user, ErrInvalidCredentials
+    }
+    return user, nil
+}
+
+// CreateSession membuat session baru dan mengembalikan token plaintext (hanya sekali)
+func (as *AuthService) CreateSession(user models.User, userAgent, ipAddress string) (string, models.Session, error) {
+    raw := make([]byte, 32)
+    if _, err := rand.Read(raw); err != nil {
+        return "", models.Session{}, err
+    }
+    token := SessionTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+    session := models.Session{
+        UserID:    user.ID,
+        TokenHash: hashToken(token),
+        UserAgent: userAgent,
+        IPAddress: ipAddress,
+        ExpiresAt: time.Now().Add(SessionTTL),
+    }
+    if err := config.DB.Create(&session).Error; err != nil {
+        return "", models.Session{}, err
+    }
+    return token, session, nil
+}
+
+// ResolveSession memvalidasi token dan mengembalikan session beserta user pemiliknya
+func (as *AuthService) ResolveSession(token string) (models.Session, error) {
+    var session models.Session
+    err := config.DB.Preload("User").
+        Where("token_hash = ?", hashToken(token)).
+        Where("revoked_at IS NULL").
+        Where("expires_at > ?", time.Now()).
+        First(&session).Error
+    if err != nil || session.User.ID == 0 {
+        return session, ErrInvalidSession
+    }
+
+    now := time.Now()
+    config.DB.Model(&session).UpdateColumn("last_used_at", now)
+    session.LastUsedAt = &now
+    return session, nil
+}
+
+func (as *AuthService) RevokeSession(sessionID uint) error {
+    return config.DB.Model(&models.Session{}).
+        Where("id = ? AND revoked_at IS NULL", sessionID).
+        Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserSessions mencabut semua session aktif milik user, kecuali exceptID (0 = cabut semua)
+func (as *AuthService) RevokeUserSessions(tx *gorm.DB, userID uint, exceptID uint) error {
+    return tx.Model(&models.Session{}).
+        Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+        Update("revoked_at", time.Now()).Error
+}
+
+func hashToken(token string) string {
+    sum := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(sum[:])
+}
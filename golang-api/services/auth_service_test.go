@@ -0,0 +1,100 @@
+package services
+
+import (
+    "database/sql/driver"
+    "errors"
+    "fmt"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/internal/fakedb"
+    "taskflow-api/models"
+    "testing"
+    "time"
+)
+
+// useFakeSessions - database palsu dengan satu session aktif milik user 7; token lain dianggap dicabut / kedaluwarsa
+func useFakeSessions(t *testing.T, activeToken string) *fakedb.DB {
+    t.Helper()
+    db, fake := fakedb.Open(t, func(query string, args []driver.Value) fakedb.Result {
+        switch {
+        case strings.Contains(query, `FROM "sessions"`) && len(args) > 0 && args[0] == hashToken(activeToken):
+            if !strings.Contains(query, "revoked_at IS NULL") || !strings.Contains(query, "expires_at >") {
+                t.Errorf("session lookup does not filter revoked or expired sessions: %s", query)
+            }
+            now := time.Now()
+            return fakedb.Result{
+                Columns: []string{"id", "user_id", "token_hash", "expires_at", "created_at"},
+                Rows:    [][]driver.Value{{int64(3), int64(7), hashToken(activeToken), now.Add(time.Hour), now}},
+            }
+        case strings.Contains(query, `FROM "users"`):
+            return fakedb.Result{
+                Columns: []string{"id", "name", "email", "role"},
+                Rows:    [][]driver.Value{{int64(7), "Native", "native@example.com", models.RoleMember}},
+            }
+        }
+        return fakedb.Result{}
+    })
+    fake.AcceptWrites()
+
+    previous := config.DB
+    config.DB = db
+    t.Cleanup(func() { config.DB = previous })
+    return fake
+}
+
+func TestResolveSession(t *testing.T) {
+    const token = SessionTokenPrefix + "active"
+    useFakeSessions(t, token)
+    authService := NewAuthService()
+
+    session, err := authService.ResolveSession(token)
+    if err != nil {
+        t.Fatalf("ResolveSession(active) = %v", err)
+    }
+    if session.ID != 3 || session.User.ID != 7 {
+        t.Errorf("session = %d for user %d, want 3 for user 7", session.ID, session.User.ID)
+    }
+
+    if _, err := authService.ResolveSession(SessionTokenPrefix + "revoked"); !errors.Is(err, ErrInvalidSession) {
+        t.Errorf("ResolveSession(revoked) = %v, want ErrInvalidSession", err)
+    }
+}
+
+func TestRevokeSessions(t *testing.T) {
+    tests := []struct {
+        name     string
+        revoke   func(as *AuthService) error
+        wantSQL  string
+        wantArgs []string
+    }{
+        {"current session", func(as *AuthService) error { return as.RevokeSession(3) }, "id = $2 AND revoked_at IS NULL", []string{"3"}},
+        // Ganti password: session lain dicabut, session yang sedang dipakai tetap aktif
+        {"other sessions", func(as *AuthService) error { return as.RevokeUserSessions(config.DB, 7, 3) }, "user_id = $2 AND id <> $3 AND revoked_at IS NULL", []string{"7", "3"}},
+        {"every session", func(as *AuthService) error { return as.RevokeUserSessions(config.DB, 7, 0) }, "user_id = $2 AND id <> $3 AND revoked_at IS NULL", []string{"7", "0"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fake := useFakeSessions(t, "")
+
+            if err := tt.revoke(NewAuthService()); err != nil {
+                t.Fatalf("revoke = %v", err)
+            }
+            writes := fake.WriteStatements()
+            if len(writes) != 1 {
+                t.Fatalf("writes = %v, want one update", writes)
+            }
+            write := writes[0]
+            if !strings.HasPrefix(write.Query, `UPDATE "sessions" SET "revoked_at"=$1`) || !strings.Contains(write.Query, tt.wantSQL) {
+                t.Errorf("query = %s, want revoked_at update with %s", write.Query, tt.wantSQL)
+            }
+            if len(write.Args) != len(tt.wantArgs)+1 {
+                t.Fatalf("args = %v, want revoked_at and %v", write.Args, tt.wantArgs)
+            }
+            for i, want := range tt.wantArgs {
+                if got := fmt.Sprint(write.Args[i+1]); got != want {
+                    t.Errorf("arg %d = %s, want %s", i+2, got, want)
+                }
+            }
+        })
+    }
+}
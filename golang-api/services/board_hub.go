@@ -0,0 +1,314 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "taskflow-api/models"
+
+    "gorm.io/gorm"
+)
+
+const (
+    // boardClientBuffer - client yang tertinggal sebanyak ini dianggap lambat dan diputus
+    boardClientBuffer = 64
+    // MaxBoardTopics - jumlah topic maksimal per koneksi
+    MaxBoardTopics = 20
+)
+
+// Topic board: "category:<id>" (board kategori) atau "user:<id>" (board task milik user)
+const (
+    BoardTopicCategory = "category"
+    BoardTopicUser     = "user"
+)
+
+// Tipe pesan WebSocket board
+const (
+    BoardCommandSubscribe   = "subscribe"
+    BoardCommandUnsubscribe = "unsubscribe"
+    BoardCommandPing        = "ping"
+
+    BoardMessageSubscribed   = "subscribed"
+    BoardMessageUnsubscribed = "unsubscribed"
+    BoardMessagePresence     = "presence"
+    BoardMessagePong         = "pong"
+    BoardMessageError        = "error"
+)
+
+// BoardEvents - event task dari outbox yang diteruskan ke board
+var BoardEvents = []string{
+    models.EventTaskCreated,
+    models.EventTaskUpdated,
+    models.EventTaskStatusChanged,
+    models.EventTaskDeleted,
+}
+
+// BoardCommand - pesan dari client
+type BoardCommand struct {
+    Type  string `json:"type"`
+    Topic string `json:"topic"`
+}
+
+// BoardMessage - pesan ke client; Type berisi salah satu BoardMessage* atau tipe event task (mis. task.updated)
+type BoardMessage struct {
+    Type    string          `json:"type"`
+    Topic   string          `json:"topic,omitempty"`
+    Data    json.RawMessage `json:"data,omitempty"`
+    ActorID uint            `json:"actor_id,omitempty"` // user yang melakukan perubahan, client bisa mengabaikan perubahannya sendiri
+    Viewers []BoardViewer   `json:"viewers,omitempty"`
+    Error   string          `json:"error,omitempty"`
+}
+
+type BoardViewer struct {
+    UserID uint   `json:"user_id"`
+    Name   string `json:"name"`
+}
+
+// BoardClient - satu koneksi WebSocket. Pesan keluar diantrikan di Outgoing; channel ditutup
+// saat client terlalu lambat, disconnect, atau hub ditutup.
+type BoardClient struct {
+    user   models.User
+    send   chan []byte
+    topics map[string]bool // topic -> shared (semua anggota board melihat task satu sama lain)
+}
+
+func (bc *BoardClient) Outgoing() <-chan []byte {
+    return bc.send
+}
+
+// BoardHub membagikan perubahan task ke client yang membuka board yang sama, beserta presence-nya
+type BoardHub struct {
+    mu      sync.Mutex
+    clients map[*BoardClient]bool
+    topics  map[string]map[*BoardClient]bool
+    closed  bool
+}
+
+var (
+    boardsOnce sync.Once
+    boards     *BoardHub
+)
+
+// Boards mengembalikan hub bersama yang dipakai controller dan outbox
+func Boards() *BoardHub {
+    boardsOnce.Do(func() {
+        boards = NewBoardHub()
+    })
+    return boards
+}
+
+func NewBoardHub() *BoardHub {
+    return &BoardHub{
+        clients: make(map[*BoardClient]bool),
+        topics:  make(map[string]map[*BoardClient]bool),
+    }
+}
+
+// ParseBoardTopic memvalidasi topic "category:<id>" / "user:<id>"
+func ParseBoardTopic(topic string) (string, uint, bool) {
+    kind, value, found := strings.Cut(topic, ":")
+    if !found || (kind != BoardTopicCategory && kind != BoardTopicUser) {
+        return "", 0, false
+    }
+    id, err := strconv.ParseUint(value, 10, 64)
+    if err != nil || id == 0 {
+        return "", 0, false
+    }
+    return kind, uint(id), true
+}
+
+// Connect mendaftarkan koneksi baru; false jika hub sudah ditutup (server shutdown)
+func (bh *BoardHub) Connect(user models.User) (*BoardClient, bool) {
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+    if bh.closed {
+        return nil, false
+    }
+
+    client := &BoardClient{
+        user:   user,
+        send:   make(chan []byte, boardClientBuffer),
+        topics: make(map[string]bool),
+    }
+    bh.clients[client] = true
+    return client, true
+}
+
+// Disconnect melepas client dari semua topic dan mengabarkan presence yang baru
+func (bh *BoardHub) Disconnect(client *BoardClient) {
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+    bh.remove(client)
+}
+
+// Subscribe memasukkan client ke topic. Akses sudah dicek pemanggil; shared berarti client
+// juga menerima perubahan task milik anggota board lain.
+func (bh *BoardHub) Subscribe(client *BoardClient, topic string, shared bool) error {
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+
+    if !bh.clients[client] {
+        return fmt.Errorf("connection closed")
+    }
+    if _, exists := client.topics[topic]; !exists && len(client.topics) >= MaxBoardTopics {
+        return fmt.Errorf("too many subscriptions, max %d", MaxBoardTopics)
+    }
+
+    client.topics[topic] = shared
+    if bh.topics[topic] == nil {
+        bh.topics[topic] = make(map[*BoardClient]bool)
+    }
+    bh.topics[topic][client] = true
+
+    bh.enqueue(client, BoardMessage{Type: BoardMessageSubscribed, Topic: topic, Viewers: bh.viewers(client, topic)})
+    bh.broadcastPresence(topic, client)
+    return nil
+}
+
+func (bh *BoardHub) Unsubscribe(client *BoardClient, topic string) {
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+
+    if _, exists := client.topics[topic]; !exists {
+        return
+    }
+    bh.leave(client, topic)
+    bh.enqueue(client, BoardMessage{Type: BoardMessageUnsubscribed, Topic: topic})
+}
+
+// Send mengantrikan pesan untuk satu client (mis. pong atau error)
+func (bh *BoardHub) Send(client *BoardClient, message BoardMessage) {
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+    bh.enqueue(client, message)
+}
+
+// Close memutus semua koneksi; dipanggil saat shutdown
+func (bh *BoardHub) Close() {
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+
+    bh.closed = true
+    for client := range bh.clients {
+        bh.remove(client)
+    }
+}
+
+// HandleOutboxEvent - consumer outbox; perubahan task diteruskan ke board user pemilik,
+// board kategorinya, dan board kategori lama jika task dipindah
+func (bh *BoardHub) HandleOutboxEvent(ctx context.Context, tx *gorm.DB, event models.OutboxEvent) error {
+    var payload struct {
+        Task struct {
+            UserID     uint `json:"user_id"`
+            CategoryID uint `json:"category_id"`
+        } `json:"task"`
+        PreviousCategoryID uint `json:"previous_category_id"`
+    }
+    if err := event.Decode(&payload); err != nil {
+        return err
+    }
+
+    message := BoardMessage{Type: event.EventType, Data: json.RawMessage(event.Payload), ActorID: event.ActorID}
+    topics := []string{
+        fmt.Sprintf("%s:%d", BoardTopicUser, payload.Task.UserID),
+        fmt.Sprintf("%s:%d", BoardTopicCategory, payload.Task.CategoryID),
+    }
+    if payload.PreviousCategoryID != 0 && payload.PreviousCategoryID != payload.Task.CategoryID {
+        topics = append(topics, fmt.Sprintf("%s:%d", BoardTopicCategory, payload.PreviousCategoryID))
+    }
+
+    bh.mu.Lock()
+    defer bh.mu.Unlock()
+    for _, topic := range topics {
+        message.Topic = topic
+        for client := range bh.topics[topic] {
+            if bh.canSee(client, topic, payload.Task.UserID) {
+                bh.enqueue(client, message)
+            }
+        }
+    }
+    return nil
+}
+
+// canSee - di board kategori global, client hanya menerima task miliknya sendiri (kecuali bisa membaca semua data)
+func (bh *BoardHub) canSee(client *BoardClient, topic string, ownerID uint) bool {
+    if client.topics[topic] || client.user.ID == ownerID {
+        return true
+    }
+    return client.user.Can(models.PermissionReadAllData)
+}
+
+// enqueue - client yang antriannya penuh diputus supaya tidak menahan client lain (backpressure)
+func (bh *BoardHub) enqueue(client *BoardClient, message BoardMessage) {
+    if !bh.clients[client] {
+        return
+    }
+    body, err := json.Marshal(message)
+    if err != nil {
+        log.Printf("⚠️  Failed to encode board message %s: %v", message.Type, err)
+        return
+    }
+
+    select {
+    case client.send <- body:
+    default:
+        log.Printf("⚠️  Board client of user %d is too slow, disconnecting", client.user.ID)
+        bh.remove(client)
+    }
+}
+
+func (bh *BoardHub) remove(client *BoardClient) {
+    if !bh.clients[client] {
+        return
+    }
+    delete(bh.clients, client)
+    close(client.send)
+    for topic := range client.topics {
+        bh.leave(client, topic)
+    }
+}
+
+func (bh *BoardHub) leave(client *BoardClient, topic string) {
+    delete(client.topics, topic)
+    delete(bh.topics[topic], client)
+    if len(bh.topics[topic]) == 0 {
+        delete(bh.topics, topic)
+        return
+    }
+    bh.broadcastPresence(topic, nil)
+}
+
+// broadcastPresence hanya untuk client di board shared; di board kategori global user tidak
+// saling berbagi task, jadi juga tidak boleh melihat siapa yang sedang membukanya
+func (bh *BoardHub) broadcastPresence(topic string, except *BoardClient) {
+    for client := range bh.topics[topic] {
+        if client != except && client.topics[topic] {
+            bh.enqueue(client, BoardMessage{Type: BoardMessagePresence, Topic: topic, Viewers: bh.viewers(client, topic)})
+        }
+    }
+}
+
+// viewers - user yang sedang membuka topic; satu user dengan beberapa tab dihitung sekali.
+// Di board yang tidak shared, client hanya melihat dirinya sendiri.
+func (bh *BoardHub) viewers(client *BoardClient, topic string) []BoardViewer {
+    if !client.topics[topic] {
+        return []BoardViewer{{UserID: client.user.ID, Name: client.user.Name}}
+    }
+
+    seen := make(map[uint]bool)
+    var viewers []BoardViewer
+    for viewer := range bh.topics[topic] {
+        if seen[viewer.user.ID] {
+            continue
+        }
+        seen[viewer.user.ID] = true
+        viewers = append(viewers, BoardViewer{UserID: viewer.user.ID, Name: viewer.user.Name})
+    }
+    sort.Slice(viewers, func(i, j int) bool { return viewers[i].UserID < viewers[j].UserID })
+    return viewers
+}
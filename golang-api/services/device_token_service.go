@@ -0,0 +1,96 @@
+package services
+
+import (
+    "log"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "firebase.google.com/go/v4/messaging"
+    "gorm.io/gorm/clause"
+)
+
+type DeviceTokenService struct{}
+
+func NewDeviceTokenService() *DeviceTokenService {
+    return &DeviceTokenService{}
+}
+
+// RegisterDevice menyimpan token device; token yang sudah terdaftar dipindah ke user ini
+// (mis. device dipakai login akun lain) dan last_seen-nya diperbarui
+func (dts *DeviceTokenService) RegisterDevice(userID uint, token, platform, userAgent string) (models.DeviceToken, error) {
+    device := models.DeviceToken{
+        UserID:     userID,
+        Token:      token,
+        Platform:   platform,
+        UserAgent:  userAgent,
+        LastSeenAt: time.Now(),
+    }
+
+    err := config.DB.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "token"}},
+        DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "user_agent", "last_seen_at", "updated_at"}),
+    }).Create(&device).Error
+    if err != nil {
+        return device, err
+    }
+
+    err = config.DB.Where("token = ?", token).First(&device).Error
+    return device, err
+}
+
+// UnregisterDevice menghapus token milik user; mengembalikan false jika tidak ditemukan
+func (dts *DeviceTokenService) UnregisterDevice(userID uint, token string) (bool, error) {
+    result := config.DB.Where("user_id = ? AND token = ?", userID, token).Delete(&models.DeviceToken{})
+    return result.RowsAffected > 0, result.Error
+}
+
+func (dts *DeviceTokenService) TokensForUser(userID uint) []string {
+    var tokens []string
+    config.DB.Model(&models.DeviceToken{}).Where("user_id = ?", userID).Order("last_seen_at DESC").Pluck("token", &tokens)
+    return tokens
+}
+
+// TokensForUsers mengembalikan token semua device, dikelompokkan per user
+func (dts *DeviceTokenService) TokensForUsers(userIDs []uint) map[uint][]string {
+    var devices []models.DeviceToken
+    config.DB.Select("user_id", "token").Where("user_id IN ?", userIDs).Find(&devices)
+
+    tokens := make(map[uint][]string)
+    for _, device := range devices {
+        tokens[device.UserID] = append(tokens[device.UserID], device.Token)
+    }
+    return tokens
+}
+
+// RemoveInvalidTokens menghapus token yang dilaporkan FCM sudah tidak terdaftar / tidak valid.
+// tokens[i] harus sesuai dengan response.Responses[i].
+func (dts *DeviceTokenService) RemoveInvalidTokens(tokens []string, response *messaging.BatchResponse) {
+    if response == nil {
+        return
+    }
+
+    // INVALID_ARGUMENT juga bisa berarti payload-nya yang salah; hanya dianggap token rusak
+    // jika ada pengiriman lain di batch yang sama yang berhasil
+    payloadValid := response.SuccessCount > 0
+
+    var invalid []string
+    for i, result := range response.Responses {
+        if i >= len(tokens) || result.Error == nil {
+            continue
+        }
+        if messaging.IsUnregistered(result.Error) || messaging.IsSenderIDMismatch(result.Error) ||
+            (payloadValid && messaging.IsInvalidArgument(result.Error)) {
+            invalid = append(invalid, tokens[i])
+        }
+    }
+    if len(invalid) == 0 {
+        return
+    }
+
+    if err := config.DB.Where("token IN ?", invalid).Delete(&models.DeviceToken{}).Error; err != nil {
+        log.Printf("⚠️  Failed to remove invalid device tokens: %v", err)
+        return
+    }
+    log.Printf("🧹 Removed %d invalid device tokens", len(invalid))
+}
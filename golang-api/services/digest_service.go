@@ -0,0 +1,179 @@
+package services
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm/clause"
+)
+
+// digestListLimit - jumlah task maksimal yang ditulis per bagian digest
+const digestListLimit = 5
+
+// Digest - ringkasan task user untuk satu periode (harian / mingguan)
+type Digest struct {
+    Frequency string
+    Period    string
+    Due       []models.Task
+    Overdue   []models.Task
+    Completed []models.Task
+}
+
+func (d Digest) Empty() bool {
+    return len(d.Due) == 0 && len(d.Overdue) == 0 && len(d.Completed) == 0
+}
+
+type DigestService struct {
+    notificationService *NotificationService
+}
+
+func NewDigestService(notificationService *NotificationService) *DigestService {
+    return &DigestService{notificationService: notificationService}
+}
+
+// DigestDue mengecek apakah digest user sudah waktunya dikirim pada now (waktu lokal user).
+// Mengembalikan period key yang dipakai untuk mencegah pengiriman ganda.
+func (ds *DigestService) DigestDue(user models.User, now time.Time) (string, bool) {
+    if user.DigestFrequency != "daily" && user.DigestFrequency != "weekly" {
+        return "", false
+    }
+
+    local := now.In(user.Location())
+    if user.DigestFrequency == "weekly" && !strings.EqualFold(user.DigestWeekday, local.Weekday().String()[:3]) {
+        return "", false
+    }
+
+    sendAt, ok := models.ParseClock(user.DigestTime)
+    if !ok || local.Hour()*60+local.Minute() < sendAt {
+        return "", false
+    }
+
+    return fmt.Sprintf("%s:%s", user.DigestFrequency, local.Format("2006-01-02")), true
+}
+
+// BuildDigest mengumpulkan task yang jatuh tempo, terlambat, dan selesai pada periode digest
+func (ds *DigestService) BuildDigest(user models.User, now time.Time, period string) (Digest, error) {
+    local := now.In(user.Location())
+    startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+
+    // Harian: jatuh tempo hari ini, selesai kemarin. Mingguan: jatuh tempo 7 hari ke depan, selesai 7 hari terakhir.
+    dueUntil := startOfDay.AddDate(0, 0, 1)
+    completedFrom := startOfDay.AddDate(0, 0, -1)
+    if user.DigestFrequency == "weekly" {
+        dueUntil = startOfDay.AddDate(0, 0, 7)
+        completedFrom = startOfDay.AddDate(0, 0, -7)
+    }
+
+    digest := Digest{Frequency: user.DigestFrequency, Period: period}
+
+    err := config.DB.Where("user_id = ? AND status <> ?", user.ID, "done").
+        Where("deadline >= ? AND deadline < ?", now, dueUntil).
+        Order("deadline ASC").Find(&digest.Due).Error
+    if err != nil {
+        return digest, err
+    }
+
+    err = config.DB.Where("user_id = ? AND status <> ?", user.ID, "done").
+        Where("deadline < ?", now).
+        Order("deadline ASC").Find(&digest.Overdue).Error
+    if err != nil {
+        return digest, err
+    }
+
+    // Task tidak menyimpan waktu selesai, jadi updated_at task berstatus done dipakai sebagai perkiraan
+    err = config.DB.Where("user_id = ? AND status = ?", user.ID, "done").
+        Where("updated_at >= ? AND updated_at < ?", completedFrom, startOfDay).
+        Order("updated_at DESC").Find(&digest.Completed).Error
+    return digest, err
+}
+
+// SendDigest mengirim digest jika belum pernah dikirim untuk periode tersebut.
+// Periode diklaim dulu lewat unique index sebelum query digest dijalankan, supaya pengecekan tiap menit
+// untuk periode yang sudah terkirim tidak memakan query; klaim dilepas lagi jika pengiriman gagal.
+func (ds *DigestService) SendDigest(user models.User, now time.Time, period string) (bool, error) {
+    log := models.DigestLog{
+        UserID: user.ID,
+        Period: period,
+        SentAt: now,
+    }
+    result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&log)
+    if result.Error != nil {
+        return false, result.Error
+    }
+    if result.RowsAffected == 0 {
+        // Sudah dikirim (mis. sebelum restart atau oleh instance lain)
+        return false, nil
+    }
+
+    digest, err := ds.BuildDigest(user, now, period)
+    if err != nil {
+        // Digest dari query yang gagal bisa tampak kosong; lepas klaim supaya dicoba lagi
+        config.DB.Delete(&log)
+        return false, err
+    }
+    err = config.DB.Model(&log).UpdateColumns(map[string]interface{}{
+        "due_count":       len(digest.Due),
+        "overdue_count":   len(digest.Overdue),
+        "completed_count": len(digest.Completed),
+    }).Error
+    if err != nil {
+        config.DB.Delete(&log)
+        return false, err
+    }
+
+    // Digest kosong tetap dicatat supaya tidak dicek ulang sepanjang hari
+    if digest.Empty() {
+        return false, nil
+    }
+
+    err = ds.notificationService.Notify(context.Background(), Notification{
+        Type:  models.NotificationTypeDigest,
+        User:  user,
+        Title: RenderTemplate(user.Locale, "digest.title."+digest.Frequency, TemplateData{}),
+        Body:  digestBody(digest, user),
+        Data: map[string]string{
+            "type":            "digest",
+            "frequency":       digest.Frequency,
+            "period":          period,
+            "due_count":       fmt.Sprintf("%d", len(digest.Due)),
+            "overdue_count":   fmt.Sprintf("%d", len(digest.Overdue)),
+            "completed_count": fmt.Sprintf("%d", len(digest.Completed)),
+            "action":          "open_tasks",
+        },
+        InboxKey: fmt.Sprintf("digest:%d:%s", user.ID, period),
+    })
+    if err != nil {
+        config.DB.Delete(&log)
+        return false, err
+    }
+    return true, nil
+}
+
+// digestBody menyusun isi digest per bagian sesuai locale user
+func digestBody(digest Digest, user models.User) string {
+    var sections []string
+    sections = appendDigestSection(sections, user, "digest.due."+digest.Frequency, "digest.due_item", digest.Due)
+    sections = appendDigestSection(sections, user, "digest.overdue", "digest.overdue_item", digest.Overdue)
+    sections = appendDigestSection(sections, user, "digest.completed."+digest.Frequency, "digest.completed_item", digest.Completed)
+    return strings.Join(sections, "\n\n")
+}
+
+func appendDigestSection(sections []string, user models.User, labelKey, itemKey string, tasks []models.Task) []string {
+    if len(tasks) == 0 {
+        return sections
+    }
+
+    lines := []string{RenderTemplate(user.Locale, labelKey, TemplateData{Count: len(tasks)})}
+    for i, task := range tasks {
+        if i == digestListLimit {
+            lines = append(lines, RenderTemplate(user.Locale, "digest.more", TemplateData{Count: len(tasks) - digestListLimit}))
+            break
+        }
+        lines = append(lines, "• "+RenderTemplate(user.Locale, itemKey, templateDataForTask(task, user)))
+    }
+    return append(sections, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+    "context"
+    "crypto/tls"
+    "fmt"
+    "mime"
+    "net"
+    "net/mail"
+    "net/smtp"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+)
+
+// smtpTimeout - batas satu pengiriman jika ctx pemanggil tidak punya deadline
+const smtpTimeout = 30 * time.Second
+
+// EmailNotifier - channel email lewat SMTP. Tanpa SMTP_USERNAME dikirim tanpa auth,
+// sehingga bisa diarahkan ke SMTP sink lokal (mis. MailHog di localhost:1025).
+type EmailNotifier struct {
+    smtp config.SMTPConfig
+}
+
+func NewEmailNotifier(smtpConfig config.SMTPConfig) *EmailNotifier {
+    return &EmailNotifier{smtp: smtpConfig}
+}
+
+func (en *EmailNotifier) Channel() string {
+    return models.ChannelEmail
+}
+
+func (en *EmailNotifier) Send(ctx context.Context, notification Notification) error {
+    if notification.User.Email == "" {
+        return fmt.Errorf("user %d has no email address", notification.User.ID)
+    }
+
+    from, err := mail.ParseAddress(en.smtp.From)
+    if err != nil {
+        return fmt.Errorf("invalid SMTP_FROM: %w", err)
+    }
+
+    var auth smtp.Auth
+    if en.smtp.Username != "" {
+        auth = smtp.PlainAuth("", en.smtp.Username, en.smtp.Password, en.smtp.Host)
+    }
+
+    return en.sendMail(ctx, auth, from.Address, notification.User.Email, en.buildMessage(notification))
+}
+
+// sendMail - seperti smtp.SendMail, tapi koneksi mengikuti deadline ctx dan ditutup saat ctx dibatalkan,
+// jadi server SMTP yang macet tidak menahan worker reminder
+func (en *EmailNotifier) sendMail(ctx context.Context, auth smtp.Auth, from, to string, message []byte) error {
+    if _, ok := ctx.Deadline(); !ok {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, smtpTimeout)
+        defer cancel()
+    }
+
+    var dialer net.Dialer
+    conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(en.smtp.Host, en.smtp.Port))
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+    deadline, _ := ctx.Deadline()
+    conn.SetDeadline(deadline)
+    stop := context.AfterFunc(ctx, func() { conn.Close() })
+    defer stop()
+
+    client, err := smtp.NewClient(conn, en.smtp.Host)
+    if err != nil {
+        return err
+    }
+    defer client.Close()
+
+    if ok, _ := client.Extension("STARTTLS"); ok {
+        if err := client.StartTLS(&tls.Config{ServerName: en.smtp.Host}); err != nil {
+            return err
+        }
+    }
+    if auth != nil {
+        if err := client.Auth(auth); err != nil {
+            return err
+        }
+    }
+    if err := client.Mail(from); err != nil {
+        return err
+    }
+    if err := client.Rcpt(to); err != nil {
+        return err
+    }
+    writer, err := client.Data()
+    if err != nil {
+        return err
+    }
+    if _, err := writer.Write(message); err != nil {
+        return err
+    }
+    if err := writer.Close(); err != nil {
+        return err
+    }
+    return client.Quit()
+}
+
+func (en *EmailNotifier) buildMessage(notification Notification) []byte {
+    to := mail.Address{Name: notification.User.Name, Address: notification.User.Email}
+
+    var msg strings.Builder
+    fmt.Fprintf(&msg, "From: %s\r\n", en.smtp.From)
+    fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+    fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Title))
+    fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+    msg.WriteString("MIME-Version: 1.0\r\n")
+    msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+    msg.WriteString("\r\n")
+    msg.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+    msg.WriteString("\r\n")
+    return []byte(msg.String())
+}
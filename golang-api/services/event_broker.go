@@ -0,0 +1,195 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "log"
+    "strconv"
+    "sync"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+const (
+    // liveEventHistory - jumlah event terakhir per user yang disimpan untuk resume lewat Last-Event-ID
+    liveEventHistory = 100
+    // liveEventBuffer - subscriber yang tertinggal sebanyak ini diputus, client reconnect lalu resume
+    liveEventBuffer = 32
+)
+
+// Event live yang dikirim ke stream /api/me/events
+const (
+    LiveEventNotification = "notification.created"
+    // LiveEventReset - Last-Event-ID sudah tidak ada di history, client harus memuat ulang datanya
+    LiveEventReset = "reset"
+)
+
+// LiveEvents - event task yang diteruskan dari outbox ke stream
+var LiveEvents = []string{models.EventTaskCreated, models.EventTaskUpdated, models.EventTaskDeleted}
+
+// LiveEvent - satu event di stream SSE. ID naik terus dan dimulai dari waktu start server,
+// jadi ID dari proses sebelumnya selalu lebih kecil dan memicu reset.
+type LiveEvent struct {
+    ID     uint64
+    UserID uint
+    Type   string
+    Data   json.RawMessage
+}
+
+// LiveSubscription - koneksi stream milik satu user. Channel Events ditutup saat subscriber
+// terlalu lambat atau broker ditutup.
+type LiveSubscription struct {
+    userID uint
+    events chan LiveEvent
+}
+
+func (s *LiveSubscription) Events() <-chan LiveEvent {
+    return s.events
+}
+
+// EventBroker membagikan event ke semua koneksi stream milik user (in-process, per instance server)
+type EventBroker struct {
+    mu          sync.Mutex
+    startID     uint64
+    lastID      uint64
+    history     map[uint][]LiveEvent
+    dropped     map[uint]uint64 // ID event terakhir yang sudah dibuang dari history user
+    subscribers map[uint]map[*LiveSubscription]bool
+    closed      bool
+}
+
+var (
+    eventsOnce sync.Once
+    events     *EventBroker
+)
+
+// Events mengembalikan broker bersama yang dipakai controller, outbox dan notification service
+func Events() *EventBroker {
+    eventsOnce.Do(func() {
+        events = NewEventBroker()
+    })
+    return events
+}
+
+func NewEventBroker() *EventBroker {
+    startID := uint64(time.Now().UnixMilli()) * 1000
+    return &EventBroker{
+        startID:     startID,
+        lastID:      startID,
+        history:     make(map[uint][]LiveEvent),
+        dropped:     make(map[uint]uint64),
+        subscribers: make(map[uint]map[*LiveSubscription]bool),
+    }
+}
+
+// Publish mengirim event ke semua koneksi user dan menyimpannya di history
+func (eb *EventBroker) Publish(userID uint, eventType string, data interface{}) {
+    body, err := json.Marshal(data)
+    if err != nil {
+        log.Printf("⚠️  Failed to encode live event %s: %v", eventType, err)
+        return
+    }
+
+    eb.mu.Lock()
+    defer eb.mu.Unlock()
+    if eb.closed {
+        return
+    }
+
+    eb.lastID++
+    event := LiveEvent{ID: eb.lastID, UserID: userID, Type: eventType, Data: body}
+
+    history := append(eb.history[userID], event)
+    if len(history) > liveEventHistory {
+        eb.dropped[userID] = history[len(history)-liveEventHistory-1].ID
+        history = history[len(history)-liveEventHistory:]
+    }
+    eb.history[userID] = history
+
+    for subscription := range eb.subscribers[userID] {
+        select {
+        case subscription.events <- event:
+        default:
+            // Client terlalu lambat: putuskan, client akan reconnect dengan Last-Event-ID
+            eb.remove(subscription)
+        }
+    }
+}
+
+// Subscribe mendaftarkan koneksi baru. Jika lastEventID diisi, event setelahnya dikembalikan sebagai backlog;
+// jika ID tersebut sudah tidak ada di history, backlog berisi satu event reset.
+// Mengembalikan false jika broker sudah ditutup (server sedang shutdown).
+func (eb *EventBroker) Subscribe(userID uint, lastEventID string) (*LiveSubscription, []LiveEvent, bool) {
+    eb.mu.Lock()
+    defer eb.mu.Unlock()
+    if eb.closed {
+        return nil, nil, false
+    }
+
+    subscription := &LiveSubscription{userID: userID, events: make(chan LiveEvent, liveEventBuffer)}
+    if eb.subscribers[userID] == nil {
+        eb.subscribers[userID] = make(map[*LiveSubscription]bool)
+    }
+    eb.subscribers[userID][subscription] = true
+
+    return subscription, eb.backlog(userID, lastEventID), true
+}
+
+// Unsubscribe dipanggil saat koneksi client selesai
+func (eb *EventBroker) Unsubscribe(subscription *LiveSubscription) {
+    eb.mu.Lock()
+    defer eb.mu.Unlock()
+    eb.remove(subscription)
+}
+
+// Close memutus semua koneksi stream; dipanggil saat SIGTERM sebelum HTTP server dimatikan
+func (eb *EventBroker) Close() {
+    eb.mu.Lock()
+    defer eb.mu.Unlock()
+
+    eb.closed = true
+    for _, subscriptions := range eb.subscribers {
+        for subscription := range subscriptions {
+            eb.remove(subscription)
+        }
+    }
+}
+
+// HandleOutboxEvent - consumer outbox yang meneruskan perubahan task ke stream pemiliknya
+func (eb *EventBroker) HandleOutboxEvent(ctx context.Context, tx *gorm.DB, event models.OutboxEvent) error {
+    eb.Publish(event.UserID, event.EventType, json.RawMessage(event.Payload))
+    return nil
+}
+
+func (eb *EventBroker) backlog(userID uint, lastEventID string) []LiveEvent {
+    if lastEventID == "" {
+        return nil
+    }
+
+    lastID, err := strconv.ParseUint(lastEventID, 10, 64)
+    if err != nil || lastID < eb.startID || lastID > eb.lastID || lastID < eb.dropped[userID] {
+        return []LiveEvent{{ID: eb.lastID, UserID: userID, Type: LiveEventReset, Data: json.RawMessage("{}")}}
+    }
+
+    history := eb.history[userID]
+    for i, event := range history {
+        if event.ID > lastID {
+            return append([]LiveEvent(nil), history[i:]...)
+        }
+    }
+    return nil
+}
+
+func (eb *EventBroker) remove(subscription *LiveSubscription) {
+    subscriptions := eb.subscribers[subscription.userID]
+    if !subscriptions[subscription] {
+        return
+    }
+    delete(subscriptions, subscription)
+    if len(subscriptions) == 0 {
+        delete(eb.subscribers, subscription.userID)
+    }
+    close(subscription.events)
+}
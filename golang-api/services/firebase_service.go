@@ -6,167 +6,145 @@ import (
     "log"
     "taskflow-api/config"
     "taskflow-api/models"
-    
+    "time"
+
     "firebase.google.com/go/v4/messaging"
 )
 
-type FirebaseService struct{}
+// FirebaseService - channel push notification lewat FCM
+type FirebaseService struct {
+    deviceTokens *DeviceTokenService
+}
 
 func NewFirebaseService() *FirebaseService {
-    return &FirebaseService{}
+    return &FirebaseService{
+        deviceTokens: NewDeviceTokenService(),
+    }
 }
 
-// SendTaskReminder - Kirim notifikasi 5 menit sebelum deadline
-func (fs *FirebaseService) SendTaskReminder(task models.Task, user models.User) error {
-    if config.FirebaseMessaging == nil {
-        log.Printf("📱 [MOCK] Would send reminder to %s: Task '%s' is due in 5 minutes!", user.Name, task.Title)
-        return nil
-    }
+func (fs *FirebaseService) Channel() string {
+    return models.ChannelPush
+}
 
-    if user.FCMToken == "" {
-        return fmt.Errorf("user %s has no FCM token", user.Email)
+// Send - Kirim notifikasi ke semua device user lewat FCM (multicast)
+func (fs *FirebaseService) Send(ctx context.Context, notification Notification) error {
+    user := notification.User
+    tokens := fs.deviceTokens.TokensForUser(user.ID)
+    if len(tokens) == 0 {
+        return fmt.Errorf("user %s has no registered devices", user.Email)
     }
 
-    message := &messaging.Message{
-        Data: map[string]string{
-            "task_id":      fmt.Sprintf("%d", task.ID),
-            "task_title":   task.Title,
-            "user_id":      fmt.Sprintf("%d", task.UserID),
-            "category_id":  fmt.Sprintf("%d", task.CategoryID),
-            "type":         "task_reminder",
-            "action":       "open_task",
-        },
+    message := &messaging.MulticastMessage{
+        Data: notification.Data,
         Notification: &messaging.Notification{
-            Title: "⏰ Task Reminder - TaskFlow",
-            Body:  fmt.Sprintf("Don't forget: %s is due in 5 minutes!", task.Title),
-        },
-        Android: &messaging.AndroidConfig{
-            Notification: &messaging.AndroidNotification{
-                Icon:         "ic_notification",
-                Color:        "#3B82F6",
-                Sound:        "default",
-                Priority:     messaging.PriorityHigh,
-                ChannelID:    "task_reminders",
-            },
+            Title: notification.Title,
+            Body:  notification.Body,
         },
-        APNS: &messaging.APNSConfig{
+        Android: androidConfig(notification),
+        Tokens:  tokens,
+    }
+
+    if notification.Type == models.NotificationTypeReminder {
+        message.APNS = &messaging.APNSConfig{
             Payload: &messaging.APNSPayload{
                 Aps: &messaging.Aps{
                     Alert: &messaging.ApsAlert{
-                        Title: "⏰ Task Reminder - TaskFlow",
-                        Body:  fmt.Sprintf("Don't forget: %s is due in 5 minutes!", task.Title),
+                        Title: notification.Title,
+                        Body:  notification.Body,
                     },
                     Badge: func() *int { b := 1; return &b }(), // PERBAIKAN: gunakan pointer
                     Sound: "default",
+                    Category: notification.Category, // iOS menampilkan tombol aksi sesuai kategori ini
                 },
             },
-        },
-        Token: user.FCMToken,
+        }
     }
 
-    response, err := config.FirebaseMessaging.Send(context.Background(), message)
+    response, err := config.FirebaseMessaging.SendEachForMulticast(ctx, message)
     if err != nil {
-        log.Printf("❌ Error sending FCM reminder: %v", err)
+        log.Printf("❌ Error sending FCM %s: %v", notification.Type, err)
         return err
     }
+    fs.deviceTokens.RemoveInvalidTokens(tokens, response)
 
-    log.Printf("✅ Task reminder sent successfully: %s", response)
-    return nil
-}
-
-// SendTaskStatusUpdate - Notifikasi ketika status task berubah
-func (fs *FirebaseService) SendTaskStatusUpdate(task models.Task, user models.User, newStatus string) error {
-    if config.FirebaseMessaging == nil {
-        log.Printf("📱 [MOCK] Would notify %s: Task '%s' is now %s", user.Name, task.Title, newStatus)
-        return nil
-    }
-
-    if user.FCMToken == "" {
-        log.Printf("⚠️  User %s has no FCM token, skipping notification", user.Email)
-        return nil
-    }
-
-    statusMessages := map[string]string{
-        "in_progress": "🚀 Task has been started",
-        "done":        "✅ Task has been completed",
-        "todo":        "📋 Task has been reset to todo",
+    if response.SuccessCount == 0 {
+        return fmt.Errorf("FCM %s failed on all %d devices", notification.Type, len(tokens))
     }
 
-    statusIcons := map[string]string{
-        "in_progress": "🚀",
-        "done":        "✅",
-        "todo":        "📋",
-    }
+    log.Printf("✅ FCM %s sent to %d/%d devices", notification.Type, response.SuccessCount, len(tokens))
+    return nil
+}
 
-    message := &messaging.Message{
-        Data: map[string]string{
-            "task_id":      fmt.Sprintf("%d", task.ID),
-            "task_title":   task.Title,
-            "user_id":      fmt.Sprintf("%d", task.UserID),
-            "category_id":  fmt.Sprintf("%d", task.CategoryID),
-            "new_status":   newStatus,
-            "type":         "status_update",
-            "action":       "open_task",
-        },
-        Notification: &messaging.Notification{
-            Title: fmt.Sprintf("%s Task Updated", statusIcons[newStatus]),
-            Body:  fmt.Sprintf("%s: %s", task.Title, statusMessages[newStatus]),
-        },
-        Android: &messaging.AndroidConfig{
+func androidConfig(notification Notification) *messaging.AndroidConfig {
+    if notification.Type == models.NotificationTypeReminder {
+        return &messaging.AndroidConfig{
             Notification: &messaging.AndroidNotification{
+                // Tanpa ClickAction: tap membuka activity default, tombol aksi dibaca dari data "category"
                 Icon:      "ic_notification",
-                Color:     "#10B981",
+                Color:     "#3B82F6",
                 Sound:     "default",
-                Priority:  messaging.PriorityDefault,
-                ChannelID: "task_updates",
+                Priority:  messaging.PriorityHigh,
+                ChannelID: "task_reminders",
             },
-        },
-        Token: user.FCMToken,
+        }
     }
 
-    response, err := config.FirebaseMessaging.Send(context.Background(), message)
-    if err != nil {
-        log.Printf("❌ Error sending status update: %v", err)
-        return err
+    return &messaging.AndroidConfig{
+        Notification: &messaging.AndroidNotification{
+            Icon:      "ic_notification",
+            Color:     "#10B981",
+            Sound:     "default",
+            Priority:  messaging.PriorityDefault,
+            ChannelID: "task_updates",
+        },
     }
-
-    log.Printf("✅ Status update notification sent: %s", response)
-    return err
 }
 
-// SendBulkTaskReminders - Kirim reminder ke multiple users sekaligus
+// SendBulkTaskReminders - Kirim reminder ke semua device dari banyak user sekaligus
 func (fs *FirebaseService) SendBulkTaskReminders(tasks []models.Task) error {
     if config.FirebaseMessaging == nil {
         log.Printf("📱 [MOCK] Would send bulk reminders to %d tasks", len(tasks))
         return nil
     }
 
+    userIDs := make([]uint, 0, len(tasks))
+    for _, task := range tasks {
+        userIDs = append(userIDs, task.UserID)
+    }
+    deviceTokens := fs.deviceTokens.TokensForUsers(userIDs)
+
+    // tokens[i] adalah token tujuan messages[i], dipakai untuk membersihkan token yang tidak valid
     var messages []*messaging.Message
-    
+    var tokens []string
+
     for _, task := range tasks {
-        if task.User.FCMToken == "" {
-            continue
+        // Task berisi User (preload) supaya teks mengikuti locale dan zona waktu pemiliknya
+        offsetMinutes := 0
+        if task.Deadline != nil {
+            offsetMinutes = int(time.Until(*task.Deadline).Minutes())
         }
+        for _, token := range deviceTokens[task.UserID] {
+            message := &messaging.Message{
+                Data: map[string]string{
+                    "task_id":      fmt.Sprintf("%d", task.ID),
+                    "task_title":   task.Title,
+                    "type":         "task_reminder",
+                    "action":       "open_task",
+                },
+                Notification: &messaging.Notification{
+                    Title: RenderTemplate(task.User.Locale, "reminder.title", templateDataForTask(task, task.User)),
+                    Body:  reminderBody(task, task.User, offsetMinutes),
+                },
+                Token: token,
+            }
 
-        message := &messaging.Message{
-            Data: map[string]string{
-                "task_id":      fmt.Sprintf("%d", task.ID),
-                "task_title":   task.Title,
-                "type":         "task_reminder",
-                "action":       "open_task",
-            },
-            Notification: &messaging.Notification{
-                Title: "⏰ Task Reminder - TaskFlow",
-                Body:  fmt.Sprintf("Don't forget: %s is due in 5 minutes!", task.Title),
-            },
-            Token: task.User.FCMToken,
+            messages = append(messages, message)
+            tokens = append(tokens, token)
         }
-        
-        messages = append(messages, message)
     }
 
     if len(messages) == 0 {
-        log.Println("📱 No valid FCM tokens found for bulk reminders")
+        log.Println("📱 No registered devices found for bulk reminders")
         return nil
     }
 
@@ -184,10 +162,11 @@ func (fs *FirebaseService) SendBulkTaskReminders(tasks []models.Task) error {
             log.Printf("❌ Error sending bulk reminders: %v", err)
             return err
         }
+        fs.deviceTokens.RemoveInvalidTokens(tokens[i:end], response)
 
-        log.Printf("✅ Bulk reminders sent: %d success, %d failed", 
+        log.Printf("✅ Bulk reminders sent: %d success, %d failed",
             response.SuccessCount, response.FailureCount)
     }
 
     return nil
-}
\ No newline at end of file
+}
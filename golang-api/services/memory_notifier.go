@@ -0,0 +1,49 @@
+package services
+
+import (
+    "context"
+    "log"
+    "sync"
+)
+
+// MemoryNotifier menyimpan notifikasi di memori tanpa benar-benar mengirim.
+// Dipakai saat channel asli tidak dikonfigurasi dan untuk memeriksa notifikasi di test.
+type MemoryNotifier struct {
+    channel string
+
+    mu   sync.Mutex
+    sent []Notification
+}
+
+func NewMemoryNotifier(channel string) *MemoryNotifier {
+    return &MemoryNotifier{channel: channel}
+}
+
+func (mn *MemoryNotifier) Channel() string {
+    return mn.channel
+}
+
+func (mn *MemoryNotifier) Send(ctx context.Context, notification Notification) error {
+    mn.mu.Lock()
+    defer mn.mu.Unlock()
+
+    mn.sent = append(mn.sent, notification)
+    log.Printf("📱 [MOCK] %s to %s: %s", mn.channel, notification.User.Name, notification.Body)
+    return nil
+}
+
+// Sent mengembalikan salinan notifikasi yang sudah "terkirim"
+func (mn *MemoryNotifier) Sent() []Notification {
+    mn.mu.Lock()
+    defer mn.mu.Unlock()
+
+    sent := make([]Notification, len(mn.sent))
+    copy(sent, mn.sent)
+    return sent
+}
+
+func (mn *MemoryNotifier) Reset() {
+    mn.mu.Lock()
+    defer mn.mu.Unlock()
+    mn.sent = nil
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sync"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm/clause"
+)
+
+// Notification - pesan yang siap dikirim lewat channel mana pun
+type Notification struct {
+    Type     string
+    User     models.User
+    Title    string
+    Body     string
+    Data     map[string]string
+    Category string // kategori aksi (tombol) untuk push notification
+    Urgent   bool   // tetap dikirim saat jam tenang
+    InboxKey string // kunci unik di inbox, supaya pengiriman ulang tidak membuat entri ganda
+}
+
+// Notifier - satu channel pengiriman notifikasi (push, email, webhook)
+type Notifier interface {
+    Channel() string
+    Send(ctx context.Context, notification Notification) error
+}
+
+// DeferredError - notifikasi tidak dikirim karena jam tenang user; kirim ulang pada Until
+type DeferredError struct {
+    Until time.Time
+}
+
+func (e *DeferredError) Error() string {
+    return fmt.Sprintf("notification deferred until %s (quiet hours)", e.Until.Format(time.RFC3339))
+}
+
+type NotificationService struct {
+    notifiers map[string]Notifier
+    inbox     bool // simpan setiap notifikasi ke tabel notifications
+}
+
+var (
+    notificationsOnce sync.Once
+    notifications     *NotificationService
+)
+
+// Notifications mengembalikan instance bersama; dibuat saat pertama dipakai, setelah config diinisialisasi
+func Notifications() *NotificationService {
+    notificationsOnce.Do(func() {
+        notifications = NewDefaultNotificationService()
+    })
+    return notifications
+}
+
+func NewNotificationService(notifiers ...Notifier) *NotificationService {
+    ns := &NotificationService{notifiers: make(map[string]Notifier)}
+    for _, notifier := range notifiers {
+        ns.notifiers[notifier.Channel()] = notifier
+    }
+    return ns
+}
+
+// NewDefaultNotificationService memakai channel sesuai konfigurasi server:
+// FCM (atau channel in-memory jika Firebase tidak aktif), SMTP jika dikonfigurasi, dan webhook
+func NewDefaultNotificationService() *NotificationService {
+    var push Notifier = NewMemoryNotifier(models.ChannelPush)
+    if config.FirebaseMessaging != nil {
+        push = NewFirebaseService()
+    }
+
+    notifiers := []Notifier{push, NewWebhookNotifier()}
+    if config.SMTP != nil {
+        notifiers = append(notifiers, NewEmailNotifier(*config.SMTP))
+    }
+
+    ns := NewNotificationService(notifiers...)
+    ns.inbox = true
+    return ns
+}
+
+// Channels mengembalikan nama channel yang tersedia di server
+func (ns *NotificationService) Channels() []string {
+    var channels []string
+    for _, channel := range models.NotificationChannels {
+        if _, ok := ns.notifiers[channel]; ok {
+            channels = append(channels, channel)
+        }
+    }
+    return channels
+}
+
+// Notify menyimpan notifikasi ke inbox lalu mengirimnya ke semua channel pilihan user untuk tipe tersebut.
+// Inbox bukan channel pengiriman: Notify gagal jika semua channel yang dicoba gagal, walaupun notifikasi
+// sudah tersimpan di inbox, supaya pemanggil bisa mengulang (InboxKey mencegah entri inbox ganda).
+// Berhasil jika minimal satu channel terkirim; tanpa channel yang bisa dicoba, hasil penyimpanan inbox dipakai.
+func (ns *NotificationService) Notify(ctx context.Context, notification Notification) error {
+    now := time.Now()
+    if !notification.Urgent && notification.User.InQuietHours(now) {
+        return &DeferredError{Until: notification.User.NextNotificationTime(now)}
+    }
+
+    var inboxErr error
+    if ns.inbox {
+        if err := saveToInbox(notification); err != nil {
+            log.Printf("❌ Failed to store %s notification for %s: %v", notification.Type, notification.User.Email, err)
+            inboxErr = fmt.Errorf("inbox: %w", err)
+        }
+    }
+
+    var errs []error
+    attempted := 0
+    for _, channel := range notification.User.ChannelsFor(notification.Type) {
+        notifier, ok := ns.notifiers[channel]
+        if !ok {
+            continue
+        }
+        attempted++
+        if err := notifier.Send(ctx, notification); err != nil {
+            log.Printf("❌ %s notification via %s failed for %s: %v", notification.Type, channel, notification.User.Email, err)
+            errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+        }
+    }
+
+    if attempted == 0 {
+        return inboxErr
+    }
+    if len(errs) < attempted {
+        return nil
+    }
+    return errors.Join(errs...)
+}
+
+func saveToInbox(notification Notification) error {
+    record := models.Notification{
+        UserID: notification.User.ID,
+        Type:   notification.Type,
+        Title:  notification.Title,
+        Body:   notification.Body,
+        Data:   notification.Data,
+    }
+
+    db := config.DB
+    if notification.InboxKey != "" {
+        key := notification.InboxKey
+        record.InboxKey = &key
+        db = db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "inbox_key"}}, DoNothing: true})
+    }
+    result := db.Create(&record)
+    if result.Error != nil {
+        return result.Error
+    }
+    if notification.InboxKey != "" && result.RowsAffected == 0 {
+        // Sudah tersimpan pada percobaan sebelumnya
+        return nil
+    }
+
+    // Client yang terhubung ke /api/me/events langsung menerima notifikasi baru
+    Events().Publish(record.UserID, LiveEventNotification, record)
+    return nil
+}
+
+// SendTaskReminder - Kirim notifikasi reminder sebelum deadline.
+// Reminder yang tersimpan (ID != 0) membawa action snooze/acknowledge di payload.
+func (ns *NotificationService) SendTaskReminder(task models.Task, user models.User, reminder models.TaskReminder) error {
+    offsetMinutes := reminderOffset(task, reminder)
+
+    notification := Notification{
+        Type:  models.NotificationTypeReminder,
+        User:  user,
+        Title: RenderTemplate(user.Locale, "reminder.title", templateDataForTask(task, user)),
+        Body:  reminderBody(task, user, offsetMinutes),
+        Data: map[string]string{
+            "task_id":        fmt.Sprintf("%d", task.ID),
+            "task_title":     task.Title,
+            "user_id":        fmt.Sprintf("%d", task.UserID),
+            "category_id":    fmt.Sprintf("%d", task.CategoryID),
+            "type":           "task_reminder",
+            "action":         "open_task",
+            "offset_minutes": fmt.Sprintf("%d", offsetMinutes),
+        },
+        Urgent: isUrgentReminder(task, user),
+    }
+    if reminder.ID != 0 {
+        notification.Data["reminder_id"] = fmt.Sprintf("%d", reminder.ID)
+        notification.Data["actions"] = models.ReminderActionSnooze + "," + models.ReminderActionAcknowledge
+        notification.Data["snooze_minutes"] = fmt.Sprintf("%d", models.DefaultSnoozeMinutes)
+        notification.Data["category"] = models.ReminderActionCategory
+        notification.Category = models.ReminderActionCategory
+        notification.InboxKey = fmt.Sprintf("reminder:%d:%d", reminder.ID, reminder.DueAt().Unix())
+    }
+
+    return ns.Notify(context.Background(), notification)
+}
+
+// SendTaskStatusUpdate - Notifikasi ketika status task berubah (tidak pernah mendesak)
+func (ns *NotificationService) SendTaskStatusUpdate(task models.Task, user models.User, newStatus string) error {
+    task.Status = newStatus
+    data := templateDataForTask(task, user)
+
+    return ns.Notify(context.Background(), Notification{
+        Type:  models.NotificationTypeStatusUpdate,
+        User:  user,
+        Title: RenderTemplate(user.Locale, "status.title", data),
+        Body:  RenderTemplate(user.Locale, "status.body."+newStatus, data),
+        Data: map[string]string{
+            "task_id":     fmt.Sprintf("%d", task.ID),
+            "task_title":  task.Title,
+            "user_id":     fmt.Sprintf("%d", task.UserID),
+            "category_id": fmt.Sprintf("%d", task.CategoryID),
+            "new_status":  newStatus,
+            "type":        "status_update",
+            "action":      "open_task",
+        },
+    })
+}
+
+// isUrgentReminder - reminder tetap dikirim saat jam tenang untuk task high priority
+// atau jika deadline-nya lewat sebelum jam tenang selesai
+func isUrgentReminder(task models.Task, user models.User) bool {
+    if task.Priority == "high" {
+        return true
+    }
+    return task.Deadline != nil && !task.Deadline.After(user.NextNotificationTime(time.Now()))
+}
+
+// reminderOffset - reminder yang di-snooze tidak lagi tepat di offset-nya, jadi hitung dari sisa waktu
+func reminderOffset(task models.Task, reminder models.TaskReminder) int {
+    if reminder.SnoozedUntil != nil && task.Deadline != nil {
+        return int(time.Until(*task.Deadline).Minutes())
+    }
+    return reminder.OffsetMinutes
+}
+
+func reminderBody(task models.Task, user models.User, offsetMinutes int) string {
+    data := templateDataForTask(task, user)
+    if offsetMinutes <= 0 {
+        return RenderTemplate(user.Locale, "reminder.body_now", data)
+    }
+    data.Offset = FormatOffsetLocale(offsetMinutes, user.Locale)
+    return RenderTemplate(user.Locale, "reminder.body", data)
+}
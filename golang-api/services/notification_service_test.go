@@ -0,0 +1,175 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/internal/fakedb"
+    "taskflow-api/models"
+    "testing"
+    "time"
+)
+
+// failingNotifier - channel yang selalu gagal, untuk menguji fallback antar channel
+type failingNotifier struct {
+    channel string
+}
+
+func (fn failingNotifier) Channel() string { return fn.channel }
+
+func (fn failingNotifier) Send(ctx context.Context, notification Notification) error {
+    return errors.New("channel down")
+}
+
+func TestNotifyRoutesToUserChannels(t *testing.T) {
+    push := NewMemoryNotifier(models.ChannelPush)
+    email := NewMemoryNotifier(models.ChannelEmail)
+    webhook := NewMemoryNotifier(models.ChannelWebhook)
+    service := NewNotificationService(push, email, webhook)
+
+    user := models.User{
+        ID: 1,
+        NotificationChannels: map[string][]string{
+            models.NotificationTypeReminder: {models.ChannelEmail, models.ChannelWebhook},
+        },
+    }
+
+    tests := []struct {
+        name             string
+        notificationType string
+        wantPush         int
+        wantEmail        int
+        wantWebhook      int
+    }{
+        {"configured reminder channels", models.NotificationTypeReminder, 0, 1, 1},
+        {"default status update channel", models.NotificationTypeStatusUpdate, 1, 0, 0},
+        {"default digest channel", models.NotificationTypeDigest, 0, 1, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            push.Reset()
+            email.Reset()
+            webhook.Reset()
+
+            err := service.Notify(context.Background(), Notification{Type: tt.notificationType, User: user, Title: "t", Body: "b"})
+            if err != nil {
+                t.Fatalf("Notify() = %v", err)
+            }
+            if got := len(push.Sent()); got != tt.wantPush {
+                t.Errorf("push sent %d, want %d", got, tt.wantPush)
+            }
+            if got := len(email.Sent()); got != tt.wantEmail {
+                t.Errorf("email sent %d, want %d", got, tt.wantEmail)
+            }
+            if got := len(webhook.Sent()); got != tt.wantWebhook {
+                t.Errorf("webhook sent %d, want %d", got, tt.wantWebhook)
+            }
+        })
+    }
+}
+
+func TestNotifySkipsUnconfiguredChannels(t *testing.T) {
+    push := NewMemoryNotifier(models.ChannelPush)
+    service := NewNotificationService(push)
+
+    // Server tanpa SMTP: channel email pilihan user dilewati tanpa error
+    user := models.User{ID: 1, NotificationChannels: map[string][]string{
+        models.NotificationTypeReminder: {models.ChannelEmail, models.ChannelPush},
+    }}
+    if err := service.Notify(context.Background(), Notification{Type: models.NotificationTypeReminder, User: user}); err != nil {
+        t.Fatalf("Notify() = %v", err)
+    }
+    if got := len(push.Sent()); got != 1 {
+        t.Errorf("push sent %d, want 1", got)
+    }
+
+    if channels := service.Channels(); len(channels) != 1 || channels[0] != models.ChannelPush {
+        t.Errorf("Channels() = %v, want [push]", channels)
+    }
+}
+
+func TestNotifyFailsOnlyWhenEveryChannelFails(t *testing.T) {
+    user := models.User{ID: 1, NotificationChannels: map[string][]string{
+        models.NotificationTypeReminder: {models.ChannelPush, models.ChannelEmail},
+    }}
+    notification := Notification{Type: models.NotificationTypeReminder, User: user}
+
+    email := NewMemoryNotifier(models.ChannelEmail)
+    partial := NewNotificationService(failingNotifier{models.ChannelPush}, email)
+    if err := partial.Notify(context.Background(), notification); err != nil {
+        t.Errorf("Notify() with one working channel = %v, want nil", err)
+    }
+    if got := len(email.Sent()); got != 1 {
+        t.Errorf("email sent %d, want 1", got)
+    }
+
+    broken := NewNotificationService(failingNotifier{models.ChannelPush}, failingNotifier{models.ChannelEmail})
+    if err := broken.Notify(context.Background(), notification); err == nil {
+        t.Error("Notify() with every channel failing = nil, want error")
+    }
+}
+
+// Inbox bukan channel pengiriman: notifikasi yang hanya tersimpan di inbox tetap dilaporkan gagal
+func TestNotifyWithInboxFailsWhenEveryChannelFails(t *testing.T) {
+    db, fake := fakedb.Open(t, nil)
+    fake.AcceptWrites()
+    previous := config.DB
+    config.DB = db
+    t.Cleanup(func() { config.DB = previous })
+
+    user := models.User{ID: 1, NotificationChannels: map[string][]string{
+        models.NotificationTypeReminder: {models.ChannelPush, models.ChannelEmail},
+    }}
+    notification := Notification{Type: models.NotificationTypeReminder, User: user, InboxKey: "reminder:1:0"}
+
+    broken := NewNotificationService(failingNotifier{models.ChannelPush}, failingNotifier{models.ChannelEmail})
+    broken.inbox = true
+    if err := broken.Notify(context.Background(), notification); err == nil {
+        t.Error("Notify() with inbox and every channel failing = nil, want error")
+    }
+    writes := fake.Writes()
+    if len(writes) != 1 || !strings.Contains(writes[0], `INSERT INTO "notifications"`) || !strings.Contains(writes[0], "ON CONFLICT") {
+        t.Errorf("writes = %v, want one idempotent inbox insert", writes)
+    }
+
+    // Tanpa channel yang bisa dicoba, tersimpan di inbox sudah cukup
+    inboxOnly := NewNotificationService()
+    inboxOnly.inbox = true
+    if err := inboxOnly.Notify(context.Background(), notification); err != nil {
+        t.Errorf("Notify() with only the inbox = %v, want nil", err)
+    }
+}
+
+func TestNotifyDefersDuringQuietHours(t *testing.T) {
+    push := NewMemoryNotifier(models.ChannelPush)
+    service := NewNotificationService(push)
+
+    // Jam tenang sepanjang hari kecuali satu menit sebelum sekarang, jadi sekarang pasti di dalam jam tenang
+    now := time.Now().UTC()
+    user := models.User{
+        ID:              1,
+        Timezone:        "UTC",
+        QuietHoursStart: now.Format("15:04"),
+        QuietHoursEnd:   now.Add(-time.Minute).Format("15:04"),
+    }
+
+    err := service.Notify(context.Background(), Notification{Type: models.NotificationTypeReminder, User: user})
+    var deferred *DeferredError
+    if !errors.As(err, &deferred) {
+        t.Fatalf("Notify() = %v, want DeferredError", err)
+    }
+    if !deferred.Until.After(now) {
+        t.Errorf("deferred until %s, want after %s", deferred.Until, now)
+    }
+    if got := len(push.Sent()); got != 0 {
+        t.Errorf("push sent %d during quiet hours, want 0", got)
+    }
+
+    if err := service.Notify(context.Background(), Notification{Type: models.NotificationTypeReminder, User: user, Urgent: true}); err != nil {
+        t.Fatalf("Notify() urgent = %v", err)
+    }
+    if got := len(push.Sent()); got != 1 {
+        t.Errorf("urgent push sent %d, want 1", got)
+    }
+}
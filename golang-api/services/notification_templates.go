@@ -0,0 +1,233 @@
+package services
+
+import (
+    "fmt"
+    "log"
+    "sort"
+    "strings"
+    "taskflow-api/models"
+    "text/template"
+)
+
+// TemplateData - variabel yang tersedia di semua template notifikasi
+type TemplateData struct {
+    TaskTitle    string
+    Category     string
+    Deadline     string // hari dan jam, mis. "Sen 14:00"
+    DeadlineDate string // tanggal saja, mis. "21 Jul"
+    Offset       string // sisa waktu, mis. "2 jam"
+    Status       string
+    StatusIcon   string
+    Count        int
+}
+
+// notificationTemplateSources - setiap locale wajib mendefinisikan semua key yang ada di locale default
+var notificationTemplateSources = map[string]map[string]string{
+    "en": {
+        "reminder.title":          "⏰ Task Reminder - TaskFlow",
+        "reminder.body":           "Don't forget: {{.TaskTitle}} is due in {{.Offset}}!",
+        "reminder.body_now":       "Don't forget: {{.TaskTitle}} is due now!",
+        "status.title":            "{{.StatusIcon}} Task Updated",
+        "status.body.todo":        "{{.TaskTitle}}: 📋 Task has been reset to todo",
+        "status.body.in_progress": "{{.TaskTitle}}: 🚀 Task has been started",
+        "status.body.done":        "{{.TaskTitle}}: ✅ Task has been completed",
+        "digest.title.daily":      "☀️ Your TaskFlow daily digest",
+        "digest.title.weekly":     "📅 Your TaskFlow weekly digest",
+        "digest.due.daily":        "Due today ({{.Count}}):",
+        "digest.due.weekly":       "Due this week ({{.Count}}):",
+        "digest.overdue":          "Overdue ({{.Count}}):",
+        "digest.completed.daily":  "Completed yesterday ({{.Count}}):",
+        "digest.completed.weekly": "Completed last week ({{.Count}}):",
+        "digest.due_item":         "{{.TaskTitle}} ({{.Deadline}})",
+        "digest.overdue_item":     "{{.TaskTitle}} (since {{.DeadlineDate}})",
+        "digest.completed_item":   "{{.TaskTitle}}",
+        "digest.more":             "…and {{.Count}} more",
+    },
+    "id": {
+        "reminder.title":          "⏰ Pengingat Tugas - TaskFlow",
+        "reminder.body":           "Jangan lupa: {{.TaskTitle}} jatuh tempo dalam {{.Offset}}!",
+        "reminder.body_now":       "Jangan lupa: {{.TaskTitle}} jatuh tempo sekarang!",
+        "status.title":            "{{.StatusIcon}} Tugas Diperbarui",
+        "status.body.todo":        "{{.TaskTitle}}: 📋 Tugas dikembalikan ke todo",
+        "status.body.in_progress": "{{.TaskTitle}}: 🚀 Tugas mulai dikerjakan",
+        "status.body.done":        "{{.TaskTitle}}: ✅ Tugas telah selesai",
+        "digest.title.daily":      "☀️ Ringkasan harian TaskFlow",
+        "digest.title.weekly":     "📅 Ringkasan mingguan TaskFlow",
+        "digest.due.daily":        "Jatuh tempo hari ini ({{.Count}}):",
+        "digest.due.weekly":       "Jatuh tempo minggu ini ({{.Count}}):",
+        "digest.overdue":          "Terlambat ({{.Count}}):",
+        "digest.completed.daily":  "Selesai kemarin ({{.Count}}):",
+        "digest.completed.weekly": "Selesai minggu lalu ({{.Count}}):",
+        "digest.due_item":         "{{.TaskTitle}} ({{.Deadline}})",
+        "digest.overdue_item":     "{{.TaskTitle}} (sejak {{.DeadlineDate}})",
+        "digest.completed_item":   "{{.TaskTitle}}",
+        "digest.more":             "…dan {{.Count}} lainnya",
+    },
+}
+
+// Satuan waktu per locale: bentuk tunggal dan jamak
+var offsetUnits = map[string]map[string][2]string{
+    "en": {"day": {"day", "days"}, "hour": {"hour", "hours"}, "minute": {"minute", "minutes"}, "now": {"now", "now"}},
+    "id": {"day": {"hari", "hari"}, "hour": {"jam", "jam"}, "minute": {"menit", "menit"}, "now": {"sekarang", "sekarang"}},
+}
+
+var (
+    shortWeekdays = map[string][7]string{
+        "en": {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+        "id": {"Min", "Sen", "Sel", "Rab", "Kam", "Jum", "Sab"},
+    }
+    shortMonths = map[string][12]string{
+        "en": {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+        "id": {"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+    }
+)
+
+var statusIcons = map[string]string{
+    "todo":        "📋",
+    "in_progress": "🚀",
+    "done":        "✅",
+}
+
+var notificationTemplates = parseNotificationTemplates()
+
+func parseNotificationTemplates() map[string]map[string]*template.Template {
+    parsed := make(map[string]map[string]*template.Template, len(notificationTemplateSources))
+    for locale, sources := range notificationTemplateSources {
+        parsed[locale] = make(map[string]*template.Template, len(sources))
+        for key, source := range sources {
+            parsed[locale][key] = template.Must(template.New(locale + ":" + key).Option("missingkey=error").Parse(source))
+        }
+    }
+    return parsed
+}
+
+// ResolveLocale memilih locale yang didukung: locale user, lalu bahasa dasarnya (id-ID -> id), lalu default
+func ResolveLocale(locale string) string {
+    locale = strings.ToLower(strings.TrimSpace(locale))
+    if _, ok := notificationTemplates[locale]; ok {
+        return locale
+    }
+    if base, _, found := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-"); found {
+        if _, ok := notificationTemplates[base]; ok {
+            return base
+        }
+    }
+    return models.DefaultLocale
+}
+
+// RenderTemplate merender template untuk locale user; key yang tidak ada di locale tersebut memakai locale default
+func RenderTemplate(locale, key string, data TemplateData) string {
+    locale = ResolveLocale(locale)
+
+    tmpl, ok := notificationTemplates[locale][key]
+    if !ok {
+        tmpl, ok = notificationTemplates[models.DefaultLocale][key]
+    }
+    if !ok {
+        log.Printf("⚠️  Notification template %q not found", key)
+        return key
+    }
+
+    var out strings.Builder
+    if err := tmpl.Execute(&out, data); err != nil {
+        log.Printf("⚠️  Failed to render notification template %q (%s): %v", key, locale, err)
+        return key
+    }
+    return out.String()
+}
+
+// ValidateTemplates memastikan setiap locale punya semua template dan semuanya bisa dirender.
+// Dipanggil saat startup supaya template yang rusak langsung ketahuan.
+func ValidateTemplates() error {
+    sample := TemplateData{
+        TaskTitle:    "Sample task",
+        Category:     "Work",
+        Deadline:     "Mon 14:00",
+        DeadlineDate: "Jul 21",
+        Offset:       "5 minutes",
+        Status:       "done",
+        StatusIcon:   "✅",
+        Count:        3,
+    }
+
+    var problems []string
+    for locale := range notificationTemplates {
+        for key := range notificationTemplates[models.DefaultLocale] {
+            tmpl, ok := notificationTemplates[locale][key]
+            if !ok {
+                problems = append(problems, fmt.Sprintf("%s: missing %q", locale, key))
+                continue
+            }
+            var out strings.Builder
+            if err := tmpl.Execute(&out, sample); err != nil {
+                problems = append(problems, fmt.Sprintf("%s: %q: %v", locale, key, err))
+            } else if strings.TrimSpace(out.String()) == "" {
+                problems = append(problems, fmt.Sprintf("%s: %q renders empty", locale, key))
+            }
+        }
+        if _, ok := offsetUnits[locale]; !ok {
+            problems = append(problems, fmt.Sprintf("%s: missing offset units", locale))
+        }
+        if _, ok := shortWeekdays[locale]; !ok {
+            problems = append(problems, fmt.Sprintf("%s: missing weekday names", locale))
+        }
+        if _, ok := shortMonths[locale]; !ok {
+            problems = append(problems, fmt.Sprintf("%s: missing month names", locale))
+        }
+    }
+    for _, locale := range models.SupportedLocales {
+        if _, ok := notificationTemplates[locale]; !ok {
+            problems = append(problems, fmt.Sprintf("%s: no templates", locale))
+        }
+    }
+
+    if len(problems) > 0 {
+        sort.Strings(problems)
+        return fmt.Errorf("invalid notification templates: %s", strings.Join(problems, "; "))
+    }
+    return nil
+}
+
+// templateDataForTask mengisi variabel task; deadline diformat di zona waktu dan bahasa user
+func templateDataForTask(task models.Task, user models.User) TemplateData {
+    locale := ResolveLocale(user.Locale)
+    data := TemplateData{
+        TaskTitle:  task.Title,
+        Category:   task.Category.Name,
+        Status:     task.Status,
+        StatusIcon: statusIcons[task.Status],
+    }
+    if task.Deadline != nil {
+        local := task.Deadline.In(user.Location())
+        data.Deadline = fmt.Sprintf("%s %s", shortWeekdays[locale][local.Weekday()], local.Format("15:04"))
+        data.DeadlineDate = fmt.Sprintf("%d %s", local.Day(), shortMonths[locale][local.Month()-1])
+        if locale == "en" {
+            data.DeadlineDate = fmt.Sprintf("%s %d", shortMonths[locale][local.Month()-1], local.Day())
+        }
+    }
+    return data
+}
+
+// FormatOffsetLocale mengubah offset menit menjadi teks sesuai locale, mis. "2 hours" atau "2 jam"
+func FormatOffsetLocale(minutes int, locale string) string {
+    units := offsetUnits[ResolveLocale(locale)]
+    plural := func(n int, unit string) string {
+        if n == 1 {
+            return fmt.Sprintf("1 %s", units[unit][0])
+        }
+        return fmt.Sprintf("%d %s", n, units[unit][1])
+    }
+
+    switch {
+    case minutes <= 0:
+        return units["now"][0]
+    case minutes%(24*60) == 0:
+        return plural(minutes/(24*60), "day")
+    case minutes%60 == 0:
+        return plural(minutes/60, "hour")
+    case minutes > 60:
+        return plural(minutes/60, "hour") + " " + plural(minutes%60, "minute")
+    default:
+        return plural(minutes, "minute")
+    }
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+    "strings"
+    "taskflow-api/models"
+    "testing"
+    "time"
+)
+
+func TestEveryTemplateRendersInEverySupportedLocale(t *testing.T) {
+    data := TemplateData{
+        TaskTitle:    "Write report",
+        Category:     "Work",
+        Deadline:     "Mon 14:00",
+        DeadlineDate: "Jul 21",
+        Offset:       "5 minutes",
+        Status:       "done",
+        StatusIcon:   "✅",
+        Count:        3,
+    }
+
+    for _, locale := range models.SupportedLocales {
+        templates, ok := notificationTemplateSources[locale]
+        if !ok {
+            t.Errorf("%s: no templates defined", locale)
+            continue
+        }
+        for key := range notificationTemplateSources[models.DefaultLocale] {
+            if _, ok := templates[key]; !ok {
+                t.Errorf("%s: missing template %q", locale, key)
+                continue
+            }
+            out := RenderTemplate(locale, key, data)
+            if strings.TrimSpace(out) == "" || out == key {
+                t.Errorf("%s: template %q did not render, got %q", locale, key, out)
+            }
+            if strings.Contains(out, "{{") || strings.Contains(out, "<no value>") {
+                t.Errorf("%s: template %q left placeholders: %q", locale, key, out)
+            }
+        }
+        for key := range templates {
+            if _, ok := notificationTemplateSources[models.DefaultLocale][key]; !ok {
+                t.Errorf("%s: template %q does not exist in the default locale", locale, key)
+            }
+        }
+    }
+
+    if err := ValidateTemplates(); err != nil {
+        t.Fatalf("ValidateTemplates() = %v", err)
+    }
+}
+
+func TestRenderTemplateUsesLocale(t *testing.T) {
+    data := TemplateData{TaskTitle: "Laporan", Offset: "2 jam"}
+    got := RenderTemplate("id", "reminder.body", data)
+    want := "Jangan lupa: Laporan jatuh tempo dalam 2 jam!"
+    if got != want {
+        t.Errorf("RenderTemplate(id) = %q, want %q", got, want)
+    }
+
+    got = RenderTemplate("fr", "reminder.body_now", data)
+    want = "Don't forget: Laporan is due now!"
+    if got != want {
+        t.Errorf("RenderTemplate(fr) = %q, want %q", got, want)
+    }
+}
+
+func TestResolveLocale(t *testing.T) {
+    tests := []struct {
+        locale string
+        want   string
+    }{
+        {"en", "en"},
+        {"id", "id"},
+        {"ID", "id"},
+        {" id ", "id"},
+        {"id-ID", "id"},
+        {"id_ID", "id"},
+        {"en-GB", "en"},
+        {"fr", "en"},
+        {"fr-FR", "en"},
+        {"", "en"},
+    }
+    for _, tt := range tests {
+        if got := ResolveLocale(tt.locale); got != tt.want {
+            t.Errorf("ResolveLocale(%q) = %q, want %q", tt.locale, got, tt.want)
+        }
+    }
+}
+
+func TestFormatOffsetLocale(t *testing.T) {
+    tests := []struct {
+        minutes int
+        locale  string
+        want    string
+    }{
+        {0, "en", "now"},
+        {-5, "id", "sekarang"},
+        {1, "en", "1 minute"},
+        {5, "en", "5 minutes"},
+        {5, "id", "5 menit"},
+        {60, "en", "1 hour"},
+        {120, "id", "2 jam"},
+        {90, "en", "1 hour 30 minutes"},
+        {90, "id", "1 jam 30 menit"},
+        {1440, "en", "1 day"},
+        {2880, "id", "2 hari"},
+        {1500, "en", "25 hours"},
+        {30, "fr", "30 minutes"},
+    }
+    for _, tt := range tests {
+        if got := FormatOffsetLocale(tt.minutes, tt.locale); got != tt.want {
+            t.Errorf("FormatOffsetLocale(%d, %q) = %q, want %q", tt.minutes, tt.locale, got, tt.want)
+        }
+    }
+}
+
+func TestTemplateDataForTaskUsesUserZoneAndLocale(t *testing.T) {
+    // Senin 07:00 UTC = Senin 14:00 WIB
+    deadline := time.Date(2025, time.July, 21, 7, 0, 0, 0, time.UTC)
+    task := models.Task{Title: "Rapat", Status: "in_progress", Deadline: &deadline}
+
+    data := templateDataForTask(task, models.User{Timezone: "Asia/Jakarta", Locale: "id"})
+    if data.Deadline != "Sen 14:00" || data.DeadlineDate != "21 Jul" {
+        t.Errorf("id: got deadline %q / %q", data.Deadline, data.DeadlineDate)
+    }
+
+    data = templateDataForTask(task, models.User{Timezone: "UTC", Locale: "en"})
+    if data.Deadline != "Mon 07:00" || data.DeadlineDate != "Jul 21" {
+        t.Errorf("en: got deadline %q / %q", data.Deadline, data.DeadlineDate)
+    }
+    if data.StatusIcon != "🚀" {
+        t.Errorf("StatusIcon = %q", data.StatusIcon)
+    }
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "net/url"
+    "syscall"
+    "time"
+)
+
+// ErrForbiddenAddress - URL mengarah ke alamat internal (loopback, jaringan privat, link-local / metadata cloud)
+var ErrForbiddenAddress = errors.New("destination address is not allowed")
+
+// blockedNetworks - rentang yang tidak tercakup helper net.IP tapi tetap tidak boleh dituju
+var blockedNetworks = mustParseCIDRs(
+    "0.0.0.0/8",     // "this network"
+    "100.64.0.0/10", // carrier-grade NAT
+    "192.0.0.0/24",  // IETF protocol assignments
+    "198.18.0.0/15", // benchmarking
+    "64:ff9b::/96",  // NAT64, bisa diterjemahkan ke alamat IPv4 internal
+)
+
+// IsForbiddenIP - alamat yang tidak boleh dihubungi oleh request keluar yang URL-nya ditentukan user
+func IsForbiddenIP(ip net.IP) bool {
+    if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+        ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+        return true
+    }
+    for _, network := range blockedNetworks {
+        if network.Contains(ip) {
+            return true
+        }
+    }
+    return false
+}
+
+// ValidateOutboundURL memastikan URL milik user adalah http/https absolut dan semua alamat host-nya publik.
+// Dipanggil saat URL disimpan; saat koneksi dibuat alamatnya dicek ulang oleh NewOutboundHTTPClient.
+func ValidateOutboundURL(ctx context.Context, rawURL string) error {
+    parsed, err := url.Parse(rawURL)
+    if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+        return errors.New("must be an absolute http or https URL")
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+    addresses, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+    if err != nil {
+        return fmt.Errorf("cannot resolve host %q", parsed.Hostname())
+    }
+    for _, address := range addresses {
+        if IsForbiddenIP(address.IP) {
+            return fmt.Errorf("host %q resolves to %s: %w", parsed.Hostname(), address.IP, ErrForbiddenAddress)
+        }
+    }
+    return nil
+}
+
+// NewOutboundHTTPClient - client untuk webhook dan notifikasi ke URL milik user. Alamat dicek di Dialer.Control
+// (setelah DNS di-resolve, jadi DNS rebinding tidak bisa mengakalinya), proxy dari environment tidak dipakai,
+// dan redirect tidak diikuti.
+func NewOutboundHTTPClient(timeout time.Duration) *http.Client {
+    dialer := &net.Dialer{
+        Timeout: 5 * time.Second,
+        Control: func(network, address string, _ syscall.RawConn) error {
+            host, _, err := net.SplitHostPort(address)
+            if err != nil {
+                return err
+            }
+            ip := net.ParseIP(host)
+            if ip == nil || IsForbiddenIP(ip) {
+                return fmt.Errorf("dial %s: %w", address, ErrForbiddenAddress)
+            }
+            return nil
+        },
+    }
+    return &http.Client{
+        Timeout: timeout,
+        Transport: &http.Transport{
+            Proxy:               nil,
+            DialContext:         dialer.DialContext,
+            TLSHandshakeTimeout: 5 * time.Second,
+            MaxIdleConns:        20,
+            IdleConnTimeout:     90 * time.Second,
+        },
+        CheckRedirect: func(*http.Request, []*http.Request) error {
+            return http.ErrUseLastResponse
+        },
+    }
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+    networks := make([]*net.IPNet, 0, len(cidrs))
+    for _, cidr := range cidrs {
+        _, network, err := net.ParseCIDR(cidr)
+        if err != nil {
+            panic(err)
+        }
+        networks = append(networks, network)
+    }
+    return networks
+}
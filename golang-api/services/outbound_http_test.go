@@ -0,0 +1,87 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestIsForbiddenIP(t *testing.T) {
+    tests := []struct {
+        ip   string
+        want bool
+    }{
+        {"127.0.0.1", true},
+        {"10.1.2.3", true},
+        {"172.16.0.1", true},
+        {"192.168.1.10", true},
+        {"169.254.169.254", true}, // metadata cloud
+        {"100.64.0.1", true},
+        {"0.0.0.0", true},
+        {"224.0.0.1", true},
+        {"::1", true},
+        {"fd00::1", true},
+        {"fe80::1", true},
+        {"::ffff:127.0.0.1", true},
+        {"64:ff9b::a00:1", true}, // NAT64 untuk 10.0.0.1
+        {"8.8.8.8", false},
+        {"1.1.1.1", false},
+        {"2001:4860:4860::8888", false},
+    }
+    for _, tt := range tests {
+        if got := IsForbiddenIP(net.ParseIP(tt.ip)); got != tt.want {
+            t.Errorf("IsForbiddenIP(%s) = %v, want %v", tt.ip, got, tt.want)
+        }
+    }
+}
+
+func TestValidateOutboundURL(t *testing.T) {
+    tests := []struct {
+        name          string
+        url           string
+        wantErr       bool
+        wantForbidden bool
+    }{
+        {"public address", "https://8.8.8.8/hook", false, false},
+        {"loopback", "http://127.0.0.1:8080/hook", true, true},
+        {"ipv6 loopback", "http://[::1]/hook", true, true},
+        {"metadata", "http://169.254.169.254/latest/meta-data", true, true},
+        {"private network", "https://10.0.0.5/hook", true, true},
+        {"unsupported scheme", "ftp://8.8.8.8/hook", true, false},
+        {"relative", "/hook", true, false},
+        {"missing host", "http://", true, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := ValidateOutboundURL(context.Background(), tt.url)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("ValidateOutboundURL(%q) = %v, wantErr %v", tt.url, err, tt.wantErr)
+            }
+            if got := errors.Is(err, ErrForbiddenAddress); got != tt.wantForbidden {
+                t.Errorf("errors.Is(err, ErrForbiddenAddress) = %v, want %v", got, tt.wantForbidden)
+            }
+        })
+    }
+}
+
+// Alamat dicek ulang saat koneksi dibuat, jadi URL yang lolos validasi lalu di-resolve ke alamat internal tetap ditolak
+func TestOutboundHTTPClientRefusesInternalAddresses(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer server.Close()
+
+    client := NewOutboundHTTPClient(2 * time.Second)
+    response, err := client.Get(server.URL)
+    if err == nil {
+        response.Body.Close()
+        t.Fatal("request to loopback server succeeded, want error")
+    }
+    if !errors.Is(err, ErrForbiddenAddress) {
+        t.Errorf("err = %v, want ErrForbiddenAddress", err)
+    }
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "sync"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+const (
+    outboxBatchSize   = 100
+    outboxBaseBackoff = 5 * time.Second
+    outboxMaxBackoff  = 10 * time.Minute
+
+    // outboxBroadcastLookback - event dibaca ulang selama jeda ini, supaya event dari transaksi yang commit
+    // belakangan (ID-nya lebih kecil dari event yang sudah terbaca) tetap ter-broadcast
+    outboxBroadcastLookback = time.Minute
+)
+
+// OutboxHandler dijalankan dalam transaksi yang sama dengan penanda processed_events,
+// jadi perubahan database oleh handler dan penandanya commit atau rollback bersama
+type OutboxHandler func(ctx context.Context, tx *gorm.DB, event models.OutboxEvent) error
+
+type outboxSubscriber struct {
+    consumer string
+    events   map[string]bool // kosong = semua event
+    handler  OutboxHandler
+}
+
+// OutboxService mempublikasikan event dari tabel outbox ke subscriber in-process.
+// Event yang gagal di salah satu subscriber dicoba lagi; subscriber yang sudah berhasil dilewati.
+// Subscriber broadcast dijalankan terpisah di setiap instance, tanpa klaim di processed_events.
+type OutboxService struct {
+    mu          sync.RWMutex
+    subscribers []outboxSubscriber
+    broadcasts  []outboxSubscriber
+
+    broadcastMu   sync.Mutex
+    broadcastFrom uint               // event dengan ID <= ini sudah ada sebelum instance ini start
+    broadcasted   map[uint]time.Time // event yang sudah di-broadcast -> created_at, dibuang setelah lookback
+}
+
+var (
+    outboxOnce sync.Once
+    outbox     *OutboxService
+)
+
+// Outbox mengembalikan instance bersama tempat subscriber didaftarkan di main.go
+func Outbox() *OutboxService {
+    outboxOnce.Do(func() {
+        outbox = NewOutboxService()
+    })
+    return outbox
+}
+
+func NewOutboxService() *OutboxService {
+    return &OutboxService{}
+}
+
+// RecordEvent menulis event ke outbox memakai transaksi pemanggil.
+// Wajib dipanggil di dalam transaksi yang sama dengan perubahan datanya.
+func RecordEvent(tx *gorm.DB, event models.OutboxEvent, payload interface{}) error {
+    body, err := json.Marshal(payload)
+    if err != nil {
+        return err
+    }
+    event.Payload = string(body)
+    event.NextAttemptAt = time.Now()
+    return tx.Create(&event).Error
+}
+
+// RecordTaskEvent - payload {"task": {...}} ditambah field tambahan seperti previous_status
+func RecordTaskEvent(tx *gorm.DB, eventType string, task models.Task, actorID uint, extra map[string]interface{}) error {
+    payload := map[string]interface{}{"task": TaskEventData(task)}
+    for key, value := range extra {
+        payload[key] = value
+    }
+    return RecordEvent(tx, models.OutboxEvent{
+        AggregateType: models.AggregateTask,
+        AggregateID:   task.ID,
+        EventType:     eventType,
+        UserID:        task.UserID,
+        ActorID:       actorID,
+    }, payload)
+}
+
+// TaskEventData - representasi task di payload event dan webhook (tanpa data user)
+func TaskEventData(task models.Task) map[string]interface{} {
+    data := map[string]interface{}{
+        "id":                task.ID,
+        "title":             task.Title,
+        "description":       task.Description,
+        "status":            task.Status,
+        "priority":          task.Priority,
+        "user_id":           task.UserID,
+        "category_id":       task.CategoryID,
+        "deadline":          task.Deadline,
+        "progress":          task.Progress,
+        "recurrence_rule":   task.RecurrenceRule,
+        "series_id":         task.SeriesID,
+        "occurrence_number": task.OccurrenceNumber,
+        "created_at":        task.CreatedAt,
+        "updated_at":        task.UpdatedAt,
+    }
+    if task.Category.ID != 0 {
+        data["category"] = task.Category.Name
+    }
+    return data
+}
+
+// Subscribe mendaftarkan consumer. Nama consumer dipakai untuk tracking idempotensi, jadi harus tetap
+// di antara deploy. Tanpa eventTypes, consumer menerima semua event.
+func (ob *OutboxService) Subscribe(consumer string, handler OutboxHandler, eventTypes ...string) {
+    ob.mu.Lock()
+    defer ob.mu.Unlock()
+    ob.subscribers = append(ob.subscribers, newOutboxSubscriber(consumer, handler, eventTypes))
+}
+
+// SubscribeBroadcast mendaftarkan consumer yang menerima setiap event di setiap instance, untuk hub in-process
+// (SSE, WebSocket) yang hanya bisa menjangkau client di instance-nya sendiri. Best-effort: tidak dicatat di
+// processed_events dan tidak diulang jika gagal. Handler menerima config.DB, bukan transaksi.
+func (ob *OutboxService) SubscribeBroadcast(consumer string, handler OutboxHandler, eventTypes ...string) {
+    ob.mu.Lock()
+    defer ob.mu.Unlock()
+    ob.broadcasts = append(ob.broadcasts, newOutboxSubscriber(consumer, handler, eventTypes))
+}
+
+func newOutboxSubscriber(consumer string, handler OutboxHandler, eventTypes []string) outboxSubscriber {
+    events := make(map[string]bool, len(eventTypes))
+    for _, eventType := range eventTypes {
+        events[eventType] = true
+    }
+    return outboxSubscriber{consumer: consumer, events: events, handler: handler}
+}
+
+// Dispatch mempublikasikan event yang belum terkirim, urut sesuai ID. Mengembalikan jumlah sukses dan gagal.
+func (ob *OutboxService) Dispatch(ctx context.Context) (int, int) {
+    var events []models.OutboxEvent
+    err := config.DB.Where("published_at IS NULL AND next_attempt_at <= ?", time.Now()).
+        Order("id ASC").Limit(outboxBatchSize).Find(&events).Error
+    if err != nil {
+        log.Printf("❌ Error fetching outbox events: %v", err)
+        return 0, 0
+    }
+
+    published, failed := 0, 0
+    for _, event := range events {
+        if ctx.Err() != nil {
+            break
+        }
+
+        if err := ob.publish(ctx, event); err != nil {
+            event.Attempts++
+            log.Printf("⚠️  Outbox event %d (%s) failed, attempt %d: %v", event.ID, event.EventType, event.Attempts, err)
+            config.DB.Model(&event).UpdateColumns(map[string]interface{}{
+                "attempts":        event.Attempts,
+                "next_attempt_at": time.Now().Add(outboxBackoff(event.Attempts)),
+                "last_error":      err.Error(),
+            })
+            failed++
+            continue
+        }
+
+        config.DB.Model(&event).UpdateColumns(map[string]interface{}{
+            "published_at": time.Now(),
+            "last_error":   "",
+        })
+        published++
+    }
+    return published, failed
+}
+
+// Broadcast meneruskan event baru ke consumer broadcast di instance ini. Tidak bergantung pada published_at,
+// jadi setiap instance menerima setiap event; event yang dibuat sebelum instance start tidak dikirim.
+// Mengembalikan jumlah event yang diteruskan.
+func (ob *OutboxService) Broadcast(ctx context.Context) (int, error) {
+    ob.broadcastMu.Lock()
+    defer ob.broadcastMu.Unlock()
+
+    if ob.broadcasted == nil {
+        var lastID uint
+        if err := config.DB.Model(&models.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&lastID).Error; err != nil {
+            return 0, err
+        }
+        ob.broadcastFrom = lastID
+        ob.broadcasted = make(map[uint]time.Time)
+    }
+
+    cutoff := time.Now().Add(-outboxBroadcastLookback)
+    for id, createdAt := range ob.broadcasted {
+        if createdAt.Before(cutoff) {
+            delete(ob.broadcasted, id)
+        }
+    }
+
+    var events []models.OutboxEvent
+    err := config.DB.Where("id > ? AND created_at >= ?", ob.broadcastFrom, cutoff).Order("id ASC").Find(&events).Error
+    if err != nil {
+        return 0, err
+    }
+
+    broadcasted := 0
+    for _, event := range events {
+        if ctx.Err() != nil {
+            break
+        }
+        if _, done := ob.broadcasted[event.ID]; done {
+            continue
+        }
+        ob.broadcasted[event.ID] = event.CreatedAt
+
+        for _, subscriber := range ob.broadcastsFor(event.EventType) {
+            if err := subscriber.handler(ctx, config.DB, event); err != nil {
+                log.Printf("⚠️  Broadcast of outbox event %d (%s) to %s failed: %v", event.ID, event.EventType, subscriber.consumer, err)
+            }
+        }
+        broadcasted++
+    }
+    return broadcasted, nil
+}
+
+// Purge menghapus event yang sudah terpublikasi beserta penanda consumer-nya setelah masa retensi
+func (ob *OutboxService) Purge(olderThan time.Duration) (int64, error) {
+    cutoff := time.Now().Add(-olderThan)
+    result := config.DB.Where("published_at < ?", cutoff).Delete(&models.OutboxEvent{})
+    if result.Error != nil {
+        return 0, result.Error
+    }
+    err := config.DB.Where("processed_at < ? AND event_id NOT IN (?)", cutoff,
+        config.DB.Model(&models.OutboxEvent{}).Select("id")).Delete(&models.ProcessedEvent{}).Error
+    return result.RowsAffected, err
+}
+
+func (ob *OutboxService) publish(ctx context.Context, event models.OutboxEvent) error {
+    var errs []error
+    for _, subscriber := range ob.subscribersFor(event.EventType) {
+        err := config.DB.Transaction(func(tx *gorm.DB) error {
+            // Klaim event untuk consumer ini; jika sudah ada berarti sudah pernah diproses
+            result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedEvent{
+                Consumer:    subscriber.consumer,
+                EventID:     event.ID,
+                ProcessedAt: time.Now(),
+            })
+            if result.Error != nil || result.RowsAffected == 0 {
+                return result.Error
+            }
+            return subscriber.handler(ctx, tx, event)
+        })
+        if err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", subscriber.consumer, err))
+        }
+    }
+    return errors.Join(errs...)
+}
+
+func (ob *OutboxService) subscribersFor(eventType string) []outboxSubscriber {
+    ob.mu.RLock()
+    defer ob.mu.RUnlock()
+    return matchingSubscribers(ob.subscribers, eventType)
+}
+
+func (ob *OutboxService) broadcastsFor(eventType string) []outboxSubscriber {
+    ob.mu.RLock()
+    defer ob.mu.RUnlock()
+    return matchingSubscribers(ob.broadcasts, eventType)
+}
+
+func matchingSubscribers(subscribers []outboxSubscriber, eventType string) []outboxSubscriber {
+    var matched []outboxSubscriber
+    for _, subscriber := range subscribers {
+        if len(subscriber.events) == 0 || subscriber.events[eventType] {
+            matched = append(matched, subscriber)
+        }
+    }
+    return matched
+}
+
+// outboxBackoff - 5 detik, 10 detik, 20 detik, ... maksimal 10 menit; event tidak pernah dibuang
+func outboxBackoff(attempts int) time.Duration {
+    backoff := outboxBaseBackoff << (attempts - 1)
+    if backoff <= 0 || backoff > outboxMaxBackoff {
+        return outboxMaxBackoff
+    }
+    return backoff
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// maxSkippedOccurrences - batas occurrence yang dilewati saat series tertinggal jauh (mis. 27 tahun harian)
+const maxSkippedOccurrences = 10000
+
+// RRule - subset RFC 5545: FREQ (DAILY/WEEKLY/MONTHLY/YEARLY), INTERVAL, BYDAY (WEEKLY), COUNT, UNTIL
+type RRule struct {
+    Freq     string
+    Interval int
+    ByDay    []time.Weekday
+    Count    int
+    Until    *time.Time
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+    "MO": time.Monday,
+    "TU": time.Tuesday,
+    "WE": time.Wednesday,
+    "TH": time.Thursday,
+    "FR": time.Friday,
+    "SA": time.Saturday,
+    "SU": time.Sunday,
+}
+
+// ParseRRule mem-parse string seperti "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10"
+func ParseRRule(value string) (RRule, error) {
+    rule := RRule{Interval: 1}
+    value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+    if value == "" {
+        return rule, errors.New("recurrence rule is empty")
+    }
+
+    for _, part := range strings.Split(value, ";") {
+        kv := strings.SplitN(part, "=", 2)
+        if len(kv) != 2 {
+            return rule, fmt.Errorf("invalid rule part %q", part)
+        }
+        key, val := strings.ToUpper(strings.TrimSpace(kv[0])), strings.ToUpper(strings.TrimSpace(kv[1]))
+
+        switch key {
+        case "FREQ":
+            switch val {
+            case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+                rule.Freq = val
+            default:
+                return rule, fmt.Errorf("unsupported FREQ %q", val)
+            }
+        case "INTERVAL":
+            n, err := strconv.Atoi(val)
+            if err != nil || n < 1 {
+                return rule, fmt.Errorf("INTERVAL must be a positive number")
+            }
+            rule.Interval = n
+        case "BYDAY":
+            for _, day := range strings.Split(val, ",") {
+                weekday, ok := rruleWeekdays[day]
+                if !ok {
+                    return rule, fmt.Errorf("unsupported BYDAY value %q", day)
+                }
+                rule.ByDay = append(rule.ByDay, weekday)
+            }
+        case "COUNT":
+            n, err := strconv.Atoi(val)
+            if err != nil || n < 1 {
+                return rule, fmt.Errorf("COUNT must be a positive number")
+            }
+            rule.Count = n
+        case "UNTIL":
+            until, err := parseRRuleTime(val)
+            if err != nil {
+                return rule, err
+            }
+            rule.Until = &until
+        default:
+            return rule, fmt.Errorf("unsupported rule part %q", key)
+        }
+    }
+
+    if rule.Freq == "" {
+        return rule, errors.New("FREQ is required")
+    }
+    if len(rule.ByDay) > 0 && rule.Freq != "WEEKLY" {
+        return rule, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+    }
+    if rule.Count > 0 && rule.Until != nil {
+        return rule, errors.New("COUNT and UNTIL cannot be used together")
+    }
+    return rule, nil
+}
+
+// Next menghitung deadline occurrence berikutnya setelah occurrence ke-n dengan deadline current.
+// Mengembalikan false jika series sudah selesai (COUNT / UNTIL).
+func (r RRule) Next(current time.Time, occurrenceNumber int) (time.Time, bool) {
+    if r.Count > 0 && occurrenceNumber >= r.Count {
+        return time.Time{}, false
+    }
+
+    var next time.Time
+    switch r.Freq {
+    case "DAILY":
+        next = current.AddDate(0, 0, r.Interval)
+    case "WEEKLY":
+        next = r.nextWeekly(current)
+    case "MONTHLY":
+        next = addMonthsSkippingInvalid(current, r.Interval)
+    case "YEARLY":
+        next = addMonthsSkippingInvalid(current, 12*r.Interval)
+    }
+
+    if r.Until != nil && next.After(*r.Until) {
+        return time.Time{}, false
+    }
+    return next, true
+}
+
+// NextIn - seperti Next, tapi dihitung di zona waktu pemilik task supaya hari BYDAY dan jam deadline
+// mengikuti waktu lokal (termasuk saat DST berganti). Hasilnya dikembalikan di zona waktu current.
+func (r RRule) NextIn(current time.Time, occurrenceNumber int, loc *time.Location) (time.Time, bool) {
+    next, ok := r.Next(current.In(loc), occurrenceNumber)
+    return next.In(current.Location()), ok
+}
+
+// NextAfter mengembalikan occurrence pertama yang deadline-nya setelah after beserta nomor occurrence-nya.
+// Occurrence yang sudah lewat dilewati tanpa dibuat, tapi tetap dihitung untuk COUNT.
+func (r RRule) NextAfter(current time.Time, occurrenceNumber int, after time.Time, loc *time.Location) (time.Time, int, bool) {
+    for i := 0; i < maxSkippedOccurrences; i++ {
+        next, ok := r.NextIn(current, occurrenceNumber, loc)
+        if !ok {
+            return time.Time{}, 0, false
+        }
+        occurrenceNumber++
+        if next.After(after) {
+            return next, occurrenceNumber, true
+        }
+        current = next
+    }
+    return time.Time{}, 0, false
+}
+
+func (r RRule) nextWeekly(current time.Time) time.Time {
+    if len(r.ByDay) == 0 {
+        return current.AddDate(0, 0, 7*r.Interval)
+    }
+
+    // Minggu dimulai hari Senin (WKST=MO); cek sisa hari di minggu yang sama dulu
+    offset := (int(current.Weekday()) + 6) % 7
+    for d := offset + 1; d < 7; d++ {
+        if r.hasDay(time.Weekday((d + 1) % 7)) {
+            return current.AddDate(0, 0, d-offset)
+        }
+    }
+
+    weekStart := current.AddDate(0, 0, -offset+7*r.Interval)
+    for d := 0; d < 7; d++ {
+        if r.hasDay(time.Weekday((d + 1) % 7)) {
+            return weekStart.AddDate(0, 0, d)
+        }
+    }
+    return weekStart
+}
+
+func (r RRule) hasDay(day time.Weekday) bool {
+    for _, d := range r.ByDay {
+        if d == day {
+            return true
+        }
+    }
+    return false
+}
+
+// addMonthsSkippingInvalid - tanggal yang tidak ada (mis. 31 Februari) dilewati sesuai RFC 5545
+func addMonthsSkippingInvalid(current time.Time, months int) time.Time {
+    for k := 1; k <= 48; k++ {
+        firstOfMonth := time.Date(current.Year(), current.Month()+time.Month(months*k), 1,
+            current.Hour(), current.Minute(), current.Second(), current.Nanosecond(), current.Location())
+        candidate := firstOfMonth.AddDate(0, 0, current.Day()-1)
+        if candidate.Month() == firstOfMonth.Month() {
+            return candidate
+        }
+    }
+    return current.AddDate(0, months, 0)
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+    for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+        if t, err := time.Parse(layout, value); err == nil {
+            if layout == "20060102" {
+                // UNTIL berupa tanggal berlaku sampai akhir hari tersebut
+                t = t.Add(24*time.Hour - time.Second)
+            }
+            return t, nil
+        }
+    }
+    return time.Time{}, fmt.Errorf("invalid UNTIL value %q", value)
+}
+
+type RecurrenceService struct{}
+
+func NewRecurrenceService() *RecurrenceService {
+    return &RecurrenceService{}
+}
+
+// NextOccurrence menghitung deadline dan nomor occurrence berikutnya yang masih di masa depan,
+// di zona waktu pemilik task. false jika series sudah selesai.
+func (rs *RecurrenceService) NextOccurrence(task models.Task) (time.Time, int, bool, error) {
+    rule, err := ParseRRule(task.RecurrenceRule)
+    if err != nil {
+        return time.Time{}, 0, false, err
+    }
+
+    owner := task.User
+    if owner.ID == 0 {
+        config.DB.Select("id", "timezone").First(&owner, task.UserID)
+    }
+    deadline, number, ok := rule.NextAfter(*task.Deadline, task.OccurrenceNumber, time.Now(), owner.Location())
+    return deadline, number, ok, nil
+}
+
+// GenerateNextOccurrence membuat occurrence berikutnya dari task berulang. Occurrence yang deadline-nya
+// sudah lewat (series tertinggal) dilewati, begitu juga nomor occurrence yang sudah dihapus user.
+// Idempotent: jika sudah ada occurrence aktif setelah task ini, tidak dibuat lagi.
+func (rs *RecurrenceService) GenerateNextOccurrence(task models.Task) (*models.Task, error) {
+    if task.RecurrenceRule == "" || task.Deadline == nil || task.SeriesID == nil {
+        return nil, nil
+    }
+
+    nextDeadline, nextNumber, ok, err := rs.NextOccurrence(task)
+    if err != nil || !ok {
+        return nil, err
+    }
+
+    var next *models.Task
+    err = config.DB.Transaction(func(tx *gorm.DB) error {
+        var count int64
+        err := tx.Model(&models.Task{}).
+            Where("series_id = ? AND occurrence_number > ?", *task.SeriesID, task.OccurrenceNumber).
+            Count(&count).Error
+        if err != nil || count > 0 {
+            return err
+        }
+
+        // Occurrence yang dihapus tidak dibuat ulang, tapi series tetap berlanjut ke occurrence setelahnya
+        var deleted []int
+        err = tx.Unscoped().Model(&models.Task{}).
+            Where("series_id = ? AND occurrence_number >= ? AND deleted_at IS NOT NULL", *task.SeriesID, nextNumber).
+            Pluck("occurrence_number", &deleted).Error
+        if err != nil {
+            return err
+        }
+        skipped := make(map[int]bool, len(deleted))
+        for _, number := range deleted {
+            skipped[number] = true
+        }
+        for skipped[nextNumber] {
+            deadline := nextDeadline
+            base := task
+            base.Deadline = &deadline
+            base.OccurrenceNumber = nextNumber
+
+            var ok bool
+            nextDeadline, nextNumber, ok, err = rs.NextOccurrence(base)
+            if err != nil || !ok {
+                return err
+            }
+        }
+
+        occurrence := models.Task{
+            Title:            task.Title,
+            Description:      task.Description,
+            Status:           "todo",
+            Priority:         task.Priority,
+            UserID:           task.UserID,
+            CategoryID:       task.CategoryID,
+            Deadline:         &nextDeadline,
+            RecurrenceRule:   task.RecurrenceRule,
+            SeriesID:         task.SeriesID,
+            OccurrenceNumber: nextNumber,
+        }
+        if err := tx.Create(&occurrence).Error; err != nil {
+            return err
+        }
+
+        // Salin checklist dalam keadaan belum selesai
+        var items []models.ChecklistItem
+        tx.Where("task_id = ?", task.ID).Order("position ASC, id ASC").Find(&items)
+        for _, item := range items {
+            copied := models.ChecklistItem{
+                TaskID:   occurrence.ID,
+                Title:    item.Title,
+                Position: item.Position,
+            }
+            if err := tx.Create(&copied).Error; err != nil {
+                return err
+            }
+        }
+
+        // Jadwal reminder ikut disalin, semuanya dalam keadaan belum terkirim
+        reminderService := NewReminderService()
+        if err := reminderService.SetTaskReminders(tx, occurrence, reminderService.TaskOffsets(tx, task.ID)); err != nil {
+            return err
+        }
+        // Occurrence dibuat oleh sistem, bukan oleh user yang menyelesaikan task sebelumnya
+        if err := RecordAudit(tx, AuditContext{}, models.AuditTaskCreate, models.AggregateTask, occurrence.ID, nil, occurrence); err != nil {
+            return err
+        }
+        if err := RecordTaskEvent(tx, models.EventTaskCreated, occurrence, 0, map[string]interface{}{
+            "previous_occurrence_id": task.ID,
+        }); err != nil {
+            return err
+        }
+
+        next = &occurrence
+        return nil
+    })
+    return next, err
+}
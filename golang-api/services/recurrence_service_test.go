@@ -0,0 +1,84 @@
+package services
+
+import (
+    "testing"
+    "time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+    t.Helper()
+    loc, err := time.LoadLocation(name)
+    if err != nil {
+        t.Skipf("time zone %s not available: %v", name, err)
+    }
+    return loc
+}
+
+func mustRule(t *testing.T, value string) RRule {
+    t.Helper()
+    rule, err := ParseRRule(value)
+    if err != nil {
+        t.Fatalf("ParseRRule(%q) = %v", value, err)
+    }
+    return rule
+}
+
+func TestNextInUsesOwnerWeekday(t *testing.T) {
+    jakarta := mustLocation(t, "Asia/Jakarta")
+    rule := mustRule(t, "FREQ=WEEKLY;BYDAY=MO")
+
+    // Senin 06:00 WIB = Minggu 23:00 UTC; deadline disimpan dalam UTC
+    current := time.Date(2025, time.July, 21, 6, 0, 0, 0, jakarta).UTC()
+    next, ok := rule.NextIn(current, 1, jakarta)
+    if !ok {
+        t.Fatal("NextIn() returned false")
+    }
+
+    want := time.Date(2025, time.July, 28, 6, 0, 0, 0, jakarta)
+    if !next.Equal(want) {
+        t.Errorf("NextIn() = %s, want %s", next.In(jakarta), want)
+    }
+    if next.Location() != time.UTC {
+        t.Errorf("NextIn() location = %s, want UTC", next.Location())
+    }
+}
+
+func TestNextInKeepsLocalTimeAcrossDST(t *testing.T) {
+    newYork := mustLocation(t, "America/New_York")
+    rule := mustRule(t, "FREQ=DAILY")
+
+    // 8 Maret 2025 09:00 EST, DST mulai 9 Maret
+    current := time.Date(2025, time.March, 8, 9, 0, 0, 0, newYork).UTC()
+    next, _ := rule.NextIn(current, 1, newYork)
+
+    if got := next.In(newYork); got.Hour() != 9 || got.Day() != 9 {
+        t.Errorf("NextIn() = %s, want 2025-03-09 09:00 local", got)
+    }
+}
+
+func TestNextAfterSkipsPastOccurrences(t *testing.T) {
+    rule := mustRule(t, "FREQ=DAILY")
+    current := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+    now := time.Date(2025, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+    next, number, ok := rule.NextAfter(current, 1, now, time.UTC)
+    if !ok {
+        t.Fatal("NextAfter() returned false")
+    }
+    if want := time.Date(2025, time.January, 11, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+        t.Errorf("NextAfter() = %s, want %s", next, want)
+    }
+    if number != 11 {
+        t.Errorf("occurrence number = %d, want 11", number)
+    }
+}
+
+func TestNextAfterRespectsCountForSkippedOccurrences(t *testing.T) {
+    rule := mustRule(t, "FREQ=DAILY;COUNT=5")
+    current := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+    now := time.Date(2025, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+    if _, _, ok := rule.NextAfter(current, 1, now, time.UTC); ok {
+        t.Error("NextAfter() should end the series once COUNT is reached")
+    }
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+    "sort"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+type ReminderService struct{}
+
+func NewReminderService() *ReminderService {
+    return &ReminderService{}
+}
+
+// OffsetsForUser mengembalikan default reminder milik user, atau default aplikasi (5 menit)
+func (rs *ReminderService) OffsetsForUser(user models.User) []int {
+    if len(user.ReminderOffsets) > 0 {
+        return user.ReminderOffsets
+    }
+    return models.DefaultReminderOffsets
+}
+
+// SetTaskReminders mengganti jadwal reminder task dengan offset yang diberikan.
+// Reminder dengan offset yang sama dipertahankan (termasuk status terkirimnya).
+func (rs *ReminderService) SetTaskReminders(tx *gorm.DB, task models.Task, offsets []int) error {
+    offsets = NormalizeOffsets(offsets)
+
+    if len(offsets) == 0 {
+        return tx.Where("task_id = ?", task.ID).Delete(&models.TaskReminder{}).Error
+    }
+
+    err := tx.Where("task_id = ? AND offset_minutes NOT IN ?", task.ID, offsets).Delete(&models.TaskReminder{}).Error
+    if err != nil {
+        return err
+    }
+
+    if task.Deadline == nil {
+        return nil
+    }
+
+    for _, offset := range offsets {
+        var count int64
+        tx.Model(&models.TaskReminder{}).Where("task_id = ? AND offset_minutes = ?", task.ID, offset).Count(&count)
+        if count > 0 {
+            continue
+        }
+
+        reminder := models.TaskReminder{
+            TaskID:        task.ID,
+            OffsetMinutes: offset,
+            RemindAt:      task.Deadline.Add(-time.Duration(offset) * time.Minute),
+        }
+        if err := tx.Create(&reminder).Error; err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// RescheduleTaskReminders menghitung ulang remind_at setelah deadline berubah.
+// Reminder yang jadwal barunya masih di masa depan akan dikirim ulang.
+func (rs *ReminderService) RescheduleTaskReminders(tx *gorm.DB, task models.Task) error {
+    var reminders []models.TaskReminder
+    tx.Where("task_id = ?", task.ID).Find(&reminders)
+
+    if task.Deadline == nil {
+        return tx.Where("task_id = ?", task.ID).Delete(&models.TaskReminder{}).Error
+    }
+
+    now := time.Now()
+    for _, reminder := range reminders {
+        reminder.RemindAt = task.Deadline.Add(-time.Duration(reminder.OffsetMinutes) * time.Minute)
+        if reminder.RemindAt.After(now) {
+            reminder.SentAt = nil
+            reminder.SnoozedUntil = nil
+            reminder.AcknowledgedAt = nil
+            reminder.FailedAttempts = 0
+            reminder.RetryAt = nil
+        }
+        if err := tx.Save(&reminder).Error; err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// SnoozeReminder menunda reminder sampai until; reminder akan dikirim lagi walaupun sudah pernah terkirim
+func (rs *ReminderService) SnoozeReminder(tx *gorm.DB, reminder *models.TaskReminder, until time.Time) error {
+    reminder.SnoozedUntil = &until
+    reminder.SnoozeCount++
+    reminder.SentAt = nil
+    reminder.AcknowledgedAt = nil
+    reminder.FailedAttempts = 0
+    reminder.RetryAt = nil
+    return tx.Save(reminder).Error
+}
+
+// AcknowledgeReminder menandai reminder sudah ditanggapi sehingga tidak dikirim lagi
+func (rs *ReminderService) AcknowledgeReminder(tx *gorm.DB, reminder *models.TaskReminder) error {
+    now := time.Now()
+    reminder.AcknowledgedAt = &now
+    reminder.SnoozedUntil = nil
+    return tx.Save(reminder).Error
+}
+
+// TaskOffsets mengembalikan offset reminder yang sedang terpasang pada task
+func (rs *ReminderService) TaskOffsets(tx *gorm.DB, taskID uint) []int {
+    var offsets []int
+    tx.Model(&models.TaskReminder{}).Where("task_id = ?", taskID).Order("offset_minutes DESC").Pluck("offset_minutes", &offsets)
+    return offsets
+}
+
+// FormatOffset mengubah offset menit menjadi teks seperti "1 day", "2 hours" atau "10 minutes"
+func FormatOffset(minutes int) string {
+    return FormatOffsetLocale(minutes, models.DefaultLocale)
+}
+
+// NormalizeOffsets membuang offset negatif dan duplikat, lalu mengurutkan dari yang terjauh
+func NormalizeOffsets(offsets []int) []int {
+    seen := make(map[int]bool, len(offsets))
+    var result []int
+    for _, offset := range offsets {
+        if offset < 0 || seen[offset] {
+            continue
+        }
+        seen[offset] = true
+        result = append(result, offset)
+    }
+    sort.Sort(sort.Reverse(sort.IntSlice(result)))
+    return result
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "log"
+    "sync"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+// StatusNotificationDebounce - perubahan status beruntun dalam jendela ini digabung jadi satu notifikasi
+const StatusNotificationDebounce = 30 * time.Second
+
+const statusNotificationBatchSize = 100
+
+// StatusNotificationService mengirim notifikasi perubahan status secara asynchronous.
+// Perubahan untuk task yang sama di-debounce: hanya status terakhir yang dikirim,
+// dan tidak ada notifikasi jika status kembali ke status awal (mis. todo -> done -> todo).
+// Notifikasi yang menunggu disimpan di pending_status_notifications dan dikirim oleh StatusNotificationWorker.
+type StatusNotificationService struct {
+    notificationService *NotificationService
+    debounce            time.Duration
+}
+
+var (
+    statusNotificationsOnce sync.Once
+    statusNotifications     *StatusNotificationService
+)
+
+// StatusNotifications mengembalikan instance bersama yang dipakai main.go dan worker
+func StatusNotifications() *StatusNotificationService {
+    statusNotificationsOnce.Do(func() {
+        statusNotifications = NewStatusNotificationService(Notifications(), StatusNotificationDebounce)
+    })
+    return statusNotifications
+}
+
+func NewStatusNotificationService(notificationService *NotificationService, debounce time.Duration) *StatusNotificationService {
+    return &StatusNotificationService{
+        notificationService: notificationService,
+        debounce:            debounce,
+    }
+}
+
+// NotifyStatusChange menjadwalkan notifikasi di dalam transaksi pemanggil. Perubahan berikutnya untuk task
+// yang sama hanya menggeser send_after; from_status tetap dari perubahan pertama.
+func (sns *StatusNotificationService) NotifyStatusChange(tx *gorm.DB, taskID uint, oldStatus, newStatus string) error {
+    if oldStatus == newStatus {
+        return nil
+    }
+    return tx.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "task_id"}},
+        DoUpdates: clause.AssignmentColumns([]string{"send_after", "updated_at"}),
+    }).Create(&models.PendingStatusNotification{
+        TaskID:     taskID,
+        FromStatus: oldStatus,
+        SendAfter:  time.Now().Add(sns.debounce),
+    }).Error
+}
+
+// HandleOutboxEvent - consumer outbox untuk task.status_changed
+func (sns *StatusNotificationService) HandleOutboxEvent(ctx context.Context, tx *gorm.DB, event models.OutboxEvent) error {
+    var payload struct {
+        Task struct {
+            ID     uint   `json:"id"`
+            Status string `json:"status"`
+        } `json:"task"`
+        PreviousStatus string `json:"previous_status"`
+    }
+    if err := event.Decode(&payload); err != nil {
+        return err
+    }
+    return sns.NotifyStatusChange(tx, payload.Task.ID, payload.PreviousStatus, payload.Task.Status)
+}
+
+// DispatchDue mengirim notifikasi yang jendela debounce-nya sudah lewat. Mengembalikan jumlah terkirim dan gagal.
+func (sns *StatusNotificationService) DispatchDue(ctx context.Context) (int, int) {
+    var pending []models.PendingStatusNotification
+    err := config.DB.Where("send_after <= ?", time.Now()).
+        Order("send_after ASC").Limit(statusNotificationBatchSize).Find(&pending).Error
+    if err != nil {
+        log.Printf("❌ Error fetching pending status notifications: %v", err)
+        return 0, 0
+    }
+
+    sent, failed := 0, 0
+    for _, notification := range pending {
+        if ctx.Err() != nil {
+            break
+        }
+        delivered, err := sns.dispatch(notification)
+        if err != nil {
+            log.Printf("❌ Failed to send status notification for task %d: %v", notification.TaskID, err)
+            failed++
+        } else if delivered {
+            sent++
+        }
+    }
+    return sent, failed
+}
+
+// dispatch mengirim satu notifikasi dan menghapus barisnya, kecuali ditunda karena jam tenang
+func (sns *StatusNotificationService) dispatch(pending models.PendingStatusNotification) (bool, error) {
+    var task models.Task
+    err := config.DB.Preload("User").Preload("Category").First(&task, pending.TaskID).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        // Task sudah dihapus
+        return false, sns.done(pending)
+    }
+    if err != nil {
+        return false, err
+    }
+
+    if task.Status == pending.FromStatus {
+        log.Printf("🔁 Task %d returned to '%s', skipping status notification", task.ID, task.Status)
+        return false, sns.done(pending)
+    }
+    if !task.User.WantsStatusNotification(task.Status) {
+        return false, sns.done(pending)
+    }
+
+    err = sns.notificationService.SendTaskStatusUpdate(task, task.User, task.Status)
+    var deferred *DeferredError
+    if errors.As(err, &deferred) {
+        // Jam tenang: coba lagi setelah jam tenang selesai, status dicek ulang saat itu
+        return false, config.DB.Model(&pending).UpdateColumn("send_after", deferred.Until).Error
+    }
+
+    // Gagal kirim tidak diulang: sebagian channel (mis. inbox) mungkin sudah terkirim
+    if doneErr := sns.done(pending); doneErr != nil && err == nil {
+        err = doneErr
+    }
+    return err == nil, err
+}
+
+// done menghapus baris pending hanya jika belum digeser oleh perubahan status baru selama pengiriman
+func (sns *StatusNotificationService) done(pending models.PendingStatusNotification) error {
+    return config.DB.Where("id = ? AND send_after = ?", pending.ID, pending.SendAfter).
+        Delete(&models.PendingStatusNotification{}).Error
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+    "sort"
+    "taskflow-api/config"
+    "taskflow-api/models"
+
+    "gorm.io/gorm"
+)
+
+// RecordTaskChanges menulis satu TaskChange per field yang berbeda antara before dan after,
+// di transaksi yang sama dengan perubahan task
+func RecordTaskChanges(tx *gorm.DB, before, after models.Task, actorID uint) error {
+    diff, err := AuditDiff(before, after)
+    if err != nil {
+        return err
+    }
+
+    var changes []models.TaskChange
+    for _, field := range models.TaskHistoryFields {
+        change, changed := diff[field]
+        if !changed {
+            continue
+        }
+        changes = append(changes, models.TaskChange{
+            TaskID:   after.ID,
+            ActorID:  actorID,
+            Field:    field,
+            OldValue: change.From,
+            NewValue: change.To,
+        })
+    }
+    if len(changes) == 0 {
+        return nil
+    }
+    return tx.Create(&changes).Error
+}
+
+// TaskHistory menyusun timeline task secara kronologis: pembuatan, perubahan field, perubahan status
+// dan reminder yang terkirim. Task belum punya fitur komentar, jadi komentar belum ada di timeline.
+func TaskHistory(task models.Task) ([]models.TaskHistoryEntry, error) {
+    entries := []models.TaskHistoryEntry{{
+        Type: models.TaskHistoryCreated,
+        At:   task.CreatedAt,
+    }}
+
+    // Pembuat task diambil dari audit log; task lama (sebelum audit log ada) tanpa actor
+    var created models.AuditLog
+    err := config.DB.Where("entity_type = ? AND entity_id = ? AND action = ?", models.AggregateTask, task.ID, models.AuditTaskCreate).
+        Order("id ASC").Limit(1).Find(&created).Error
+    if err != nil {
+        return nil, err
+    }
+    entries[0].ActorID = created.ActorID
+
+    var changes []models.TaskChange
+    if err := config.DB.Where("task_id = ?", task.ID).Order("id ASC").Find(&changes).Error; err != nil {
+        return nil, err
+    }
+    for _, change := range changes {
+        entryType := models.TaskHistoryFieldChanged
+        if change.Field == "status" {
+            entryType = models.TaskHistoryStatusChanged
+        }
+        entries = append(entries, models.TaskHistoryEntry{
+            Type:    entryType,
+            At:      change.CreatedAt,
+            ActorID: change.ActorID,
+            Field:   change.Field,
+            From:    change.OldValue,
+            To:      change.NewValue,
+        })
+    }
+
+    // Setiap pengiriman tercatat sendiri, jadi reminder yang di-snooze lalu dikirim ulang muncul dua kali
+    var sends []models.TaskReminderSend
+    if err := config.DB.Where("task_id = ?", task.ID).Order("id ASC").Find(&sends).Error; err != nil {
+        return nil, err
+    }
+    for _, send := range sends {
+        offset := send.OffsetMinutes
+        entries = append(entries, models.TaskHistoryEntry{
+            Type:          models.TaskHistoryReminderSent,
+            At:            send.SentAt,
+            ReminderID:    send.ReminderID,
+            OffsetMinutes: &offset,
+        })
+    }
+
+    if err := fillActorNames(entries); err != nil {
+        return nil, err
+    }
+    sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+    return entries, nil
+}
+
+// fillActorNames - nama actor dimuat sekaligus; user yang sudah dihapus tetap ditampilkan namanya
+func fillActorNames(entries []models.TaskHistoryEntry) error {
+    var ids []uint
+    for _, entry := range entries {
+        if entry.ActorID != 0 {
+            ids = append(ids, entry.ActorID)
+        }
+    }
+    if len(ids) == 0 {
+        return nil
+    }
+
+    var users []models.User
+    if err := config.DB.Unscoped().Select("id", "name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+        return err
+    }
+    names := make(map[uint]string, len(users))
+    for _, user := range users {
+        names[user.ID] = user.Name
+    }
+    for i := range entries {
+        entries[i].ActorName = names[entries[i].ActorID]
+    }
+    return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "taskflow-api/models"
+    "time"
+)
+
+// WebhookNotifier - channel HTTP webhook; notifikasi di-POST sebagai JSON ke URL milik user
+type WebhookNotifier struct {
+    client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+    return &WebhookNotifier{
+        // URL ditentukan user: alamat internal ditolak saat dial dan redirect tidak diikuti
+        client: NewOutboundHTTPClient(10 * time.Second),
+    }
+}
+
+func (wn *WebhookNotifier) Channel() string {
+    return models.ChannelWebhook
+}
+
+func (wn *WebhookNotifier) Send(ctx context.Context, notification Notification) error {
+    url := notification.User.NotificationWebhookURL
+    if url == "" {
+        return fmt.Errorf("user %s has no webhook URL", notification.User.Email)
+    }
+
+    payload, err := json.Marshal(map[string]interface{}{
+        "type":    notification.Type,
+        "user_id": notification.User.ID,
+        "title":   notification.Title,
+        "body":    notification.Body,
+        "data":    notification.Data,
+        "sent_at": time.Now(),
+    })
+    if err != nil {
+        return err
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("User-Agent", "TaskFlow-Notifier/1.0")
+
+    resp, err := wn.client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode >= 300 {
+        return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+    }
+    return nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+    "bytes"
+    "context"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "log"
+    "net/http"
+    "strconv"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+const (
+    // WebhookMaxAttempts - setelah percobaan ke-10 delivery ditandai failed (total jeda ~4 jam)
+    WebhookMaxAttempts = 10
+    webhookBaseBackoff = 30 * time.Second
+    webhookMaxBackoff  = 2 * time.Hour
+    webhookBatchSize   = 50
+    // webhookDrainLimit - sisa response dibaca (lalu dibuang) supaya koneksi bisa dipakai ulang
+    webhookDrainLimit = 4 << 10
+)
+
+// WebhookService mengantrikan event task untuk subscription user dan mengirimnya dengan retry
+type WebhookService struct {
+    client *http.Client
+}
+
+func NewWebhookService() *WebhookService {
+    return &WebhookService{
+        // URL ditentukan user: alamat internal ditolak saat dial dan redirect tidak diikuti
+        client: NewOutboundHTTPClient(10 * time.Second),
+    }
+}
+
+// GenerateWebhookSecret membuat secret acak untuk subscription yang tidak memberikan secret sendiri
+func GenerateWebhookSecret() (string, error) {
+    raw := make([]byte, 24)
+    if _, err := rand.Read(raw); err != nil {
+        return "", err
+    }
+    return "whsec_" + hex.EncodeToString(raw), nil
+}
+
+// SignWebhookPayload - HMAC-SHA256 dari "<timestamp>.<body>", dikirim di header X-TaskFlow-Signature
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    fmt.Fprintf(mac, "%d.", timestamp)
+    mac.Write(body)
+    return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Emit mengantrikan event untuk semua subscription aktif milik user yang berlangganan event tersebut.
+// Pengiriman dilakukan oleh WebhookWorker, jadi request API tidak menunggu endpoint eksternal.
+func (ws *WebhookService) Emit(db *gorm.DB, userID uint, event, eventID string, data interface{}) error {
+    var subscriptions []models.WebhookSubscription
+    if err := db.Where("user_id = ? AND active = ?", userID, true).Find(&subscriptions).Error; err != nil {
+        return err
+    }
+
+    payload, err := json.Marshal(map[string]interface{}{
+        "id":         eventID,
+        "event":      event,
+        "created_at": time.Now(),
+        "data":       data,
+    })
+    if err != nil {
+        return err
+    }
+
+    now := time.Now()
+    var deliveries []models.WebhookDelivery
+    for _, subscription := range subscriptions {
+        if !subscription.Subscribes(event) {
+            continue
+        }
+        deliveries = append(deliveries, models.WebhookDelivery{
+            SubscriptionID: subscription.ID,
+            EventID:        eventID,
+            Event:          event,
+            Payload:        string(payload),
+            Status:         models.WebhookDeliveryPending,
+            NextAttemptAt:  &now,
+        })
+    }
+    if len(deliveries) == 0 {
+        return nil
+    }
+    return db.Create(&deliveries).Error
+}
+
+// HandleOutboxEvent - consumer outbox; delivery dibuat dalam transaksi consumer sehingga tiap event
+// hanya diantrikan sekali walaupun dipublikasikan ulang
+func (ws *WebhookService) HandleOutboxEvent(ctx context.Context, tx *gorm.DB, event models.OutboxEvent) error {
+    var data map[string]interface{}
+    if err := event.Decode(&data); err != nil {
+        return err
+    }
+    return ws.Emit(tx, event.UserID, event.EventType, fmt.Sprintf("evt_%d", event.ID), data)
+}
+
+// DeliverDue mengirim delivery pending yang sudah waktunya. Mengembalikan jumlah sukses dan gagal.
+func (ws *WebhookService) DeliverDue(ctx context.Context) (int, int) {
+    var deliveries []models.WebhookDelivery
+    err := config.DB.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, time.Now()).
+        Order("next_attempt_at ASC").Limit(webhookBatchSize).Find(&deliveries).Error
+    if err != nil {
+        log.Printf("❌ Error fetching webhook deliveries: %v", err)
+        return 0, 0
+    }
+
+    succeeded, failed := 0, 0
+    for _, delivery := range deliveries {
+        if ctx.Err() != nil {
+            break
+        }
+        if ws.Attempt(ctx, &delivery) == nil {
+            succeeded++
+        } else {
+            failed++
+        }
+    }
+    return succeeded, failed
+}
+
+// Attempt melakukan satu percobaan pengiriman dan menjadwalkan retry dengan exponential backoff jika gagal
+func (ws *WebhookService) Attempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+    var subscription models.WebhookSubscription
+    if err := config.DB.First(&subscription, delivery.SubscriptionID).Error; err != nil {
+        // Subscription sudah dihapus, delivery tidak bisa dikirim lagi
+        ws.finish(delivery, models.WebhookDeliveryFailed, 0, "subscription not found")
+        return err
+    }
+
+    status, err := ws.post(ctx, subscription, *delivery)
+    delivery.Attempts++
+    if err == nil {
+        return ws.finish(delivery, models.WebhookDeliverySucceeded, status, "")
+    }
+
+    if delivery.Attempts >= WebhookMaxAttempts {
+        log.Printf("❌ Webhook delivery %d (%s) failed permanently after %d attempts: %v",
+            delivery.ID, delivery.Event, delivery.Attempts, err)
+        ws.finish(delivery, models.WebhookDeliveryFailed, status, err.Error())
+        return err
+    }
+
+    now := time.Now()
+    next := now.Add(webhookBackoff(delivery.Attempts))
+    delivery.LastAttemptAt = &now
+    delivery.NextAttemptAt = &next
+    delivery.ResponseStatus = status
+    delivery.LastError = err.Error()
+    config.DB.Model(delivery).
+        Select("attempts", "last_attempt_at", "next_attempt_at", "response_status", "last_error").
+        Updates(delivery)
+    return err
+}
+
+// Redeliver mengantrikan ulang event yang sama (payload dan event_id tidak berubah) sebagai delivery baru
+func (ws *WebhookService) Redeliver(original models.WebhookDelivery) (models.WebhookDelivery, error) {
+    now := time.Now()
+    delivery := models.WebhookDelivery{
+        SubscriptionID: original.SubscriptionID,
+        EventID:        original.EventID,
+        Event:          original.Event,
+        Payload:        original.Payload,
+        Status:         models.WebhookDeliveryPending,
+        NextAttemptAt:  &now,
+        RedeliveryOf:   &original.ID,
+    }
+    err := config.DB.Create(&delivery).Error
+    return delivery, err
+}
+
+// post mengirim satu delivery. Isi response tidak disimpan, hanya status code-nya, supaya webhook
+// tidak bisa dipakai untuk membaca response dari layanan lain.
+func (ws *WebhookService) post(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+    body := []byte(delivery.Payload)
+    timestamp := time.Now().Unix()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+    if err != nil {
+        return 0, err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("User-Agent", "TaskFlow-Webhooks/1.0")
+    req.Header.Set("X-TaskFlow-Event", delivery.Event)
+    req.Header.Set("X-TaskFlow-Event-ID", delivery.EventID)
+    req.Header.Set("X-TaskFlow-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+    req.Header.Set("X-TaskFlow-Timestamp", strconv.FormatInt(timestamp, 10))
+    req.Header.Set("X-TaskFlow-Signature", SignWebhookPayload(subscription.Secret, timestamp, body))
+
+    resp, err := ws.client.Do(req)
+    if err != nil {
+        return 0, err
+    }
+    defer resp.Body.Close()
+    io.Copy(io.Discard, io.LimitReader(resp.Body, webhookDrainLimit))
+
+    // Redirect tidak diikuti, jadi 3xx juga dianggap gagal
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+    }
+    return resp.StatusCode, nil
+}
+
+func (ws *WebhookService) finish(delivery *models.WebhookDelivery, status string, responseStatus int, lastError string) error {
+    now := time.Now()
+    delivery.Status = status
+    delivery.LastAttemptAt = &now
+    delivery.NextAttemptAt = nil
+    delivery.ResponseStatus = responseStatus
+    delivery.LastError = lastError
+    if status == models.WebhookDeliverySucceeded {
+        delivery.DeliveredAt = &now
+    }
+    return config.DB.Model(delivery).
+        Select("status", "attempts", "last_attempt_at", "next_attempt_at", "response_status", "last_error", "delivered_at").
+        Updates(delivery).Error
+}
+
+// webhookBackoff - 30 detik, 1 menit, 2 menit, ... maksimal 2 jam
+func webhookBackoff(attempts int) time.Duration {
+    backoff := webhookBaseBackoff << (attempts - 1)
+    if backoff <= 0 || backoff > webhookMaxBackoff {
+        return webhookMaxBackoff
+    }
+    return backoff
+}
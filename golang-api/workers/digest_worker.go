@@ -0,0 +1,74 @@
+package workers
+
+import (
+    "errors"
+    "log"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/robfig/cron/v3"
+)
+
+type DigestWorker struct {
+    digestService *services.DigestService
+    cron          *cron.Cron
+}
+
+func NewDigestWorker() *DigestWorker {
+    return &DigestWorker{
+        digestService: services.NewDigestService(services.Notifications()),
+        cron:          cron.New(cron.WithSeconds()),
+    }
+}
+
+func (dw *DigestWorker) Start() {
+    // Dicek tiap menit karena jam kirim digest mengikuti timezone masing-masing user
+    _, err := dw.cron.AddFunc("30 * * * * *", dw.sendDueDigests)
+    if err != nil {
+        log.Printf("❌ Error adding digest cron job: %v", err)
+        return
+    }
+    
+    dw.cron.Start()
+    log.Println("📰 Digest worker started - checking every minute for due digests")
+}
+
+func (dw *DigestWorker) Stop() {
+    if dw.cron != nil {
+        dw.cron.Stop()
+        log.Println("📰 Digest worker stopped")
+    }
+}
+
+func (dw *DigestWorker) sendDueDigests() {
+    now := time.Now()
+    
+    var users []models.User
+    if err := config.DB.Where("digest_frequency IN ?", []string{"daily", "weekly"}).Find(&users).Error; err != nil {
+        log.Printf("❌ Error fetching digest subscribers: %v", err)
+        return
+    }
+    
+    for _, user := range users {
+        period, due := dw.digestService.DigestDue(user, now)
+        if !due {
+            continue
+        }
+        
+        sent, err := dw.digestService.SendDigest(user, now, period)
+        var deferred *services.DeferredError
+        if errors.As(err, &deferred) {
+            // Jam tenang: periode dilepas lagi, dicoba ulang di menit berikutnya
+            continue
+        }
+        if err != nil {
+            log.Printf("❌ Failed to send %s digest to %s: %v", user.DigestFrequency, user.Email, err)
+            continue
+        }
+        if sent {
+            log.Printf("📰 %s digest sent to %s (%s)", user.DigestFrequency, user.Email, period)
+        }
+    }
+}
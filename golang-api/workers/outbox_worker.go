@@ -0,0 +1,86 @@
+package workers
+
+import (
+    "context"
+    "log"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/robfig/cron/v3"
+)
+
+// OutboxRetention - event yang sudah terpublikasi disimpan selama ini untuk keperluan debugging
+const OutboxRetention = 7 * 24 * time.Hour
+
+type OutboxWorker struct {
+    outbox *services.OutboxService
+    cron   *cron.Cron
+    ctx    context.Context
+    cancel context.CancelFunc
+}
+
+func NewOutboxWorker() *OutboxWorker {
+    ctx, cancel := context.WithCancel(context.Background())
+    return &OutboxWorker{
+        outbox: services.Outbox(),
+        cron:   cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+        ctx:    ctx,
+        cancel: cancel,
+    }
+}
+
+func (ow *OutboxWorker) Start() {
+    _, err := ow.cron.AddFunc("* * * * * *", ow.dispatchEvents)
+    if err != nil {
+        log.Printf("❌ Error adding outbox cron job: %v", err)
+        return
+    }
+    
+    // Setiap instance mem-broadcast sendiri ke hub SSE / WebSocket in-process miliknya
+    _, err = ow.cron.AddFunc("* * * * * *", ow.broadcastEvents)
+    if err != nil {
+        log.Printf("❌ Error adding outbox broadcast cron job: %v", err)
+        return
+    }
+    
+    _, err = ow.cron.AddFunc("0 15 * * * *", ow.purgeEvents)
+    if err != nil {
+        log.Printf("❌ Error adding outbox purge cron job: %v", err)
+        return
+    }
+    
+    ow.cron.Start()
+    log.Println("📮 Outbox worker started - publishing domain events every second")
+}
+
+func (ow *OutboxWorker) Stop() {
+    if ow.cron != nil {
+        ow.cancel()
+        <-ow.cron.Stop().Done()
+        log.Println("📮 Outbox worker stopped")
+    }
+}
+
+func (ow *OutboxWorker) dispatchEvents() {
+    published, failed := ow.outbox.Dispatch(ow.ctx)
+    if failed > 0 {
+        log.Printf("📊 Outbox batch completed: %d published, %d failed", published, failed)
+    }
+}
+
+func (ow *OutboxWorker) broadcastEvents() {
+    if _, err := ow.outbox.Broadcast(ow.ctx); err != nil {
+        log.Printf("❌ Error broadcasting outbox events: %v", err)
+    }
+}
+
+func (ow *OutboxWorker) purgeEvents() {
+    purged, err := ow.outbox.Purge(OutboxRetention)
+    if err != nil {
+        log.Printf("❌ Error purging outbox events: %v", err)
+        return
+    }
+    if purged > 0 {
+        log.Printf("🧹 Purged %d published outbox events", purged)
+    }
+}
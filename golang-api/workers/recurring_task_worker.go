@@ -0,0 +1,96 @@
+package workers
+
+import (
+    "log"
+    "taskflow-api/config"
+    "taskflow-api/models"
+    "taskflow-api/services"
+    "time"
+
+    "github.com/robfig/cron/v3"
+)
+
+// RecurrenceLookahead - occurrence berikutnya dibuat lebih awal jika deadline-nya masuk jendela ini,
+// supaya task reminder worker sudah bisa mengirim reminder-nya
+const RecurrenceLookahead = 48 * time.Hour
+
+type RecurringTaskWorker struct {
+    recurrenceService *services.RecurrenceService
+    cron              *cron.Cron
+}
+
+func NewRecurringTaskWorker() *RecurringTaskWorker {
+    return &RecurringTaskWorker{
+        recurrenceService: services.NewRecurrenceService(),
+        cron:              cron.New(cron.WithSeconds()),
+    }
+}
+
+func (rtw *RecurringTaskWorker) Start() {
+    _, err := rtw.cron.AddFunc("0 */15 * * * *", rtw.materializeOccurrences)
+    if err != nil {
+        log.Printf("❌ Error adding recurring task cron job: %v", err)
+        return
+    }
+    
+    rtw.cron.Start()
+    log.Println("🔁 Recurring task worker started - materializing occurrences every 15 minutes")
+    
+    go rtw.materializeOccurrences()
+}
+
+func (rtw *RecurringTaskWorker) Stop() {
+    if rtw.cron != nil {
+        rtw.cron.Stop()
+        log.Println("🔁 Recurring task worker stopped")
+    }
+}
+
+func (rtw *RecurringTaskWorker) materializeOccurrences() {
+    horizon := time.Now().Add(RecurrenceLookahead)
+    
+    // Ambil occurrence aktif terakhir dari setiap series yang masih berulang; occurrence yang dihapus
+    // tidak dihitung, supaya menghapus occurrence terbaru tidak menghentikan series
+    var tasks []models.Task
+    err := config.DB.Preload("User").
+        Where("recurrence_rule <> '' AND series_id IS NOT NULL AND deadline IS NOT NULL").
+        Where("occurrence_number = (SELECT MAX(t2.occurrence_number) FROM tasks t2 WHERE t2.series_id = tasks.series_id AND t2.deleted_at IS NULL)").
+        Find(&tasks).Error
+    if err != nil {
+        log.Printf("❌ Error fetching recurring tasks: %v", err)
+        return
+    }
+    
+    created := 0
+    for _, task := range tasks {
+        // Buat occurrence berurutan sampai deadline berikutnya melewati horizon (maks. 50 per series per run).
+        // Occurrence yang sudah lewat dilewati, jadi series yang tertinggal jauh tidak menghasilkan task terlambat.
+        current := task
+        for i := 0; i < 50; i++ {
+            nextDeadline, _, ok, err := rtw.recurrenceService.NextOccurrence(current)
+            if err != nil {
+                log.Printf("⚠️  Task %d has an invalid recurrence rule: %v", current.ID, err)
+                break
+            }
+            if !ok || nextDeadline.After(horizon) {
+                break
+            }
+            
+            next, err := rtw.recurrenceService.GenerateNextOccurrence(current)
+            if err != nil {
+                log.Printf("❌ Failed to materialize occurrence for task %d: %v", current.ID, err)
+                break
+            }
+            if next == nil {
+                break
+            }
+            created++
+            next.User = task.User
+            current = *next
+        }
+    }
+    
+    if created > 0 {
+        log.Printf("🔁 Materialized %d upcoming recurring task occurrences", created)
+    }
+}
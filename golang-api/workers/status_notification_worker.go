@@ -0,0 +1,52 @@
+package workers
+
+import (
+    "context"
+    "log"
+    "taskflow-api/services"
+
+    "github.com/robfig/cron/v3"
+)
+
+type StatusNotificationWorker struct {
+    statusNotifications *services.StatusNotificationService
+    cron                *cron.Cron
+    ctx                 context.Context
+    cancel              context.CancelFunc
+}
+
+func NewStatusNotificationWorker() *StatusNotificationWorker {
+    ctx, cancel := context.WithCancel(context.Background())
+    return &StatusNotificationWorker{
+        statusNotifications: services.StatusNotifications(),
+        cron:                cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+        ctx:                 ctx,
+        cancel:              cancel,
+    }
+}
+
+func (sw *StatusNotificationWorker) Start() {
+    _, err := sw.cron.AddFunc("*/5 * * * * *", sw.sendPendingNotifications)
+    if err != nil {
+        log.Printf("❌ Error adding status notification cron job: %v", err)
+        return
+    }
+    
+    sw.cron.Start()
+    log.Println("🔔 Status notification worker started - sending debounced status updates every 5 seconds")
+}
+
+func (sw *StatusNotificationWorker) Stop() {
+    if sw.cron != nil {
+        sw.cancel()
+        <-sw.cron.Stop().Done()
+        log.Println("🔔 Status notification worker stopped")
+    }
+}
+
+func (sw *StatusNotificationWorker) sendPendingNotifications() {
+    sent, failed := sw.statusNotifications.DispatchDue(sw.ctx)
+    if failed > 0 {
+        log.Printf("📊 Status notification batch completed: %d sent, %d failed", sent, failed)
+    }
+}
@@ -1,6 +1,7 @@
 package workers
 
 import (
+    "errors"
     "log"
     "taskflow-api/config"
     "taskflow-api/models"
@@ -8,17 +9,19 @@ import (
     "time"
 
     "github.com/robfig/cron/v3"
+    "gorm.io/gorm"
 )
 
 type TaskReminderWorker struct {
-    firebaseService *services.FirebaseService
-    cron           *cron.Cron
+    notificationService *services.NotificationService
+    cron                *cron.Cron
 }
 
 func NewTaskReminderWorker() *TaskReminderWorker {
     return &TaskReminderWorker{
-        firebaseService: services.NewFirebaseService(),
-        cron:           cron.New(cron.WithSeconds()),
+        notificationService: services.Notifications(),
+        // SkipIfStillRunning: batch yang lambat tidak boleh tumpang tindih dengan batch berikutnya (reminder ganda)
+        cron:                cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
     }
 }
 
@@ -30,62 +33,110 @@ func (trw *TaskReminderWorker) Start() {
     }
     
     trw.cron.Start()
-    log.Println("⏰ Task reminder worker started - checking every minute for 5-minute deadline reminders")
+    log.Println("⏰ Task reminder worker started - checking every minute for due reminders")
 }
 
 func (trw *TaskReminderWorker) Stop() {
     if trw.cron != nil {
-        trw.cron.Stop()
+        <-trw.cron.Stop().Done()
         log.Println("⏰ Task reminder worker stopped")
     }
 }
 
+const (
+    // ReminderGracePeriod - reminder yang terlewat lebih lama dari ini (mis. server mati) tidak dikirim lagi
+    ReminderGracePeriod = time.Hour
+    reminderBaseBackoff = time.Minute
+    reminderMaxBackoff  = 15 * time.Minute
+)
+
 func (trw *TaskReminderWorker) checkTaskReminders() {
     now := time.Now()
-    fiveMinutesLater := now.Add(5 * time.Minute)
-    oneMinuteLater := now.Add(1 * time.Minute)
     
-    var tasks []models.Task
-    err := config.DB.Preload("User").Preload("Category").
-        Where("deadline BETWEEN ? AND ?", fiveMinutesLater, oneMinuteLater).
-        Where("reminder_sent_at IS NULL").
-        Where("status != ?", "done").
-        Where("deadline IS NOT NULL").
-        Find(&tasks).Error
+    var reminders []models.TaskReminder
+    err := config.DB.Preload("Task.User").Preload("Task.Category").
+        Joins("JOIN tasks ON tasks.id = task_reminders.task_id").
+        Where("task_reminders.sent_at IS NULL AND task_reminders.acknowledged_at IS NULL").
+        // Reminder yang di-snooze dikirim saat snoozed_until, bukan remind_at
+        Where("COALESCE(task_reminders.snoozed_until, task_reminders.remind_at) <= ?", now).
+        Where("COALESCE(task_reminders.snoozed_until, task_reminders.remind_at) > ?", now.Add(-ReminderGracePeriod)).
+        Where("(task_reminders.retry_at IS NULL OR task_reminders.retry_at <= ?)", now).
+        Where("tasks.deleted_at IS NULL").
+        Where("tasks.status <> ?", "done").
+        Where("tasks.deadline IS NOT NULL").
+        Find(&reminders).Error
     
     if err != nil {
-        log.Printf("❌ Error fetching tasks for reminders: %v", err)
+        log.Printf("❌ Error fetching task reminders: %v", err)
         return
     }
     
-    if len(tasks) == 0 {
+    if len(reminders) == 0 {
         return 
     }
     
-    log.Printf("⏰ Found %d tasks needing 5-minute deadline reminders", len(tasks))
+    log.Printf("⏰ Found %d due task reminders", len(reminders))
     
     successCount := 0
     failCount := 0
     
-    for _, task := range tasks {
-        if task.User.FCMToken == "" {
-            log.Printf("⚠️  User %s has no FCM token, skipping reminder for task: %s", 
-                task.User.Email, task.Title)
+    for _, reminder := range reminders {
+        task := reminder.Task
+        
+        err := trw.notificationService.SendTaskReminder(task, task.User, reminder)
+        var deferred *services.DeferredError
+        if errors.As(err, &deferred) {
+            // Jam tenang: tunda reminder sampai jam tenang user selesai
+            config.DB.Model(&reminder).UpdateColumn("snoozed_until", deferred.Until)
+            log.Printf("🌙 Reminder for task '%s' deferred until %s (quiet hours of %s)", 
+                task.Title, deferred.Until.Format(time.RFC3339), task.User.Email)
             continue
         }
-        
-        err := trw.firebaseService.SendTaskReminder(task, task.User)
         if err != nil {
-            log.Printf("❌ Failed to send reminder for task '%s' to user %s: %v", 
-                task.Title, task.User.Email, err)
+            // Dicoba lagi dengan backoff selama masih dalam grace period
+            retryAt := time.Now().Add(reminderBackoff(reminder.FailedAttempts + 1))
+            config.DB.Model(&reminder).UpdateColumns(map[string]interface{}{
+                "failed_attempts": reminder.FailedAttempts + 1,
+                "retry_at":        retryAt,
+            })
+            log.Printf("❌ Failed to send reminder for task '%s' to user %s (attempt %d, retry at %s): %v", 
+                task.Title, task.User.Email, reminder.FailedAttempts+1, retryAt.Format(time.RFC3339), err)
             failCount++
         } else {
             reminderTime := time.Now()
-            task.ReminderSentAt = &reminderTime
-            config.DB.Save(&task)
+            err := config.DB.Transaction(func(tx *gorm.DB) error {
+                err := tx.Model(&reminder).UpdateColumns(map[string]interface{}{
+                    "sent_at":         reminderTime,
+                    "failed_attempts": 0,
+                    "retry_at":        nil,
+                }).Error
+                if err != nil {
+                    return err
+                }
+                if err := tx.Model(&task).UpdateColumn("reminder_sent_at", reminderTime).Error; err != nil {
+                    return err
+                }
+                err = tx.Create(&models.TaskReminderSend{
+                    TaskID:        task.ID,
+                    ReminderID:    reminder.ID,
+                    OffsetMinutes: reminder.OffsetMinutes,
+                    SentAt:        reminderTime,
+                }).Error
+                if err != nil {
+                    return err
+                }
+                return services.RecordTaskEvent(tx, models.EventReminderSent, task, 0, map[string]interface{}{
+                    "reminder_id":    reminder.ID,
+                    "offset_minutes": reminder.OffsetMinutes,
+                    "sent_at":        reminderTime,
+                })
+            })
+            if err != nil {
+                log.Printf("⚠️  Failed to mark reminder %d as sent: %v", reminder.ID, err)
+            }
             
-            log.Printf("✅ 5-minute reminder sent for task: '%s' to %s", 
-                task.Title, task.User.Email)
+            log.Printf("✅ %s reminder sent for task: '%s' to %s", 
+                services.FormatOffset(reminder.OffsetMinutes), task.Title, task.User.Email)
             successCount++
         }
         
@@ -104,5 +155,19 @@ func (trw *TaskReminderWorker) SendImmediateReminder(taskID uint) error {
         return err
     }
     
-    return trw.firebaseService.SendTaskReminder(task, task.User)
-}
\ No newline at end of file
+    // Reminder ad-hoc tidak tersimpan, jadi tanpa action snooze/acknowledge
+    offsetMinutes := 0
+    if task.Deadline != nil {
+        offsetMinutes = int(time.Until(*task.Deadline).Minutes())
+    }
+    return trw.notificationService.SendTaskReminder(task, task.User, models.TaskReminder{OffsetMinutes: offsetMinutes})
+}
+
+// reminderBackoff - 1 menit, 2 menit, 4 menit, ... maksimal 15 menit
+func reminderBackoff(attempts int) time.Duration {
+    backoff := reminderBaseBackoff << (attempts - 1)
+    if backoff <= 0 || backoff > reminderMaxBackoff {
+        return reminderMaxBackoff
+    }
+    return backoff
+}
@@ -0,0 +1,53 @@
+package workers
+
+import (
+    "context"
+    "log"
+    "taskflow-api/services"
+
+    "github.com/robfig/cron/v3"
+)
+
+type WebhookWorker struct {
+    webhookService *services.WebhookService
+    cron           *cron.Cron
+    ctx            context.Context
+    cancel         context.CancelFunc
+}
+
+func NewWebhookWorker() *WebhookWorker {
+    ctx, cancel := context.WithCancel(context.Background())
+    return &WebhookWorker{
+        webhookService: services.NewWebhookService(),
+        // SkipIfStillRunning: batch yang lambat tidak boleh tumpang tindih dengan batch berikutnya
+        cron:           cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+        ctx:            ctx,
+        cancel:         cancel,
+    }
+}
+
+func (ww *WebhookWorker) Start() {
+    _, err := ww.cron.AddFunc("*/10 * * * * *", ww.deliverPendingWebhooks)
+    if err != nil {
+        log.Printf("❌ Error adding webhook cron job: %v", err)
+        return
+    }
+    
+    ww.cron.Start()
+    log.Println("🪝 Webhook worker started - delivering queued events every 10 seconds")
+}
+
+func (ww *WebhookWorker) Stop() {
+    if ww.cron != nil {
+        ww.cancel()
+        <-ww.cron.Stop().Done()
+        log.Println("🪝 Webhook worker stopped")
+    }
+}
+
+func (ww *WebhookWorker) deliverPendingWebhooks() {
+    succeeded, failed := ww.webhookService.DeliverDue(ww.ctx)
+    if succeeded > 0 || failed > 0 {
+        log.Printf("📊 Webhook batch completed: %d delivered, %d failed", succeeded, failed)
+    }
+}
@@ -23,9 +23,9 @@ export const userApi = {
     return apiClient.get(`/users/firebase/${firebaseUID}`);
   },
 
-  // Update FCM token
-  updateFCMToken: async (id: number, fcmToken: string): Promise<ApiResponse<User>> => {
-    return apiClient.put(`/users/${id}/fcm-token`, { fcm_token: fcmToken });
+  // Register this browser's FCM token as a device of the current user
+  registerDevice: async (token: string): Promise<ApiResponse<unknown>> => {
+    return apiClient.post('/me/devices', { token, platform: 'web' });
   },
 
   // Update user profile
@@ -43,7 +43,7 @@ export class FCMTokenService {
       const { user } = useAuthStore.getState();
       
       if (user) {
-        await userApi.updateFCMToken(user.id, token);
+        await userApi.registerDevice(token);
         console.log('FCM token updated in backend');
       }
     } catch (error) {
@@ -3,7 +3,6 @@ export interface User {
   name: string;
   email: string;
   firebase_uid?: string;
-  fcm_token?: string;
   created_at: string;
   updated_at: string;
 }